@@ -0,0 +1,393 @@
+// Code generated by gen_accessors.go; DO NOT EDIT.
+
+package goflight
+
+// GetCallsign returns the value of Callsign, ok is false if it is nil
+func (s StateVector) GetCallsign() (value string, ok bool) {
+	if s.Callsign == nil {
+		return value, false
+	}
+
+	return *s.Callsign, true
+}
+
+// GetTimePosition returns the value of TimePosition, ok is false if it is nil
+func (s StateVector) GetTimePosition() (value int64, ok bool) {
+	if s.TimePosition == nil {
+		return value, false
+	}
+
+	return *s.TimePosition, true
+}
+
+// GetLongitude returns the value of Longitude, ok is false if it is nil
+func (s StateVector) GetLongitude() (value float64, ok bool) {
+	if s.Longitude == nil {
+		return value, false
+	}
+
+	return *s.Longitude, true
+}
+
+// GetLatitude returns the value of Latitude, ok is false if it is nil
+func (s StateVector) GetLatitude() (value float64, ok bool) {
+	if s.Latitude == nil {
+		return value, false
+	}
+
+	return *s.Latitude, true
+}
+
+// GetBaroAltitude returns the value of BaroAltitude, ok is false if it is nil
+func (s StateVector) GetBaroAltitude() (value float64, ok bool) {
+	if s.BaroAltitude == nil {
+		return value, false
+	}
+
+	return *s.BaroAltitude, true
+}
+
+// GetVelocity returns the value of Velocity, ok is false if it is nil
+func (s StateVector) GetVelocity() (value float64, ok bool) {
+	if s.Velocity == nil {
+		return value, false
+	}
+
+	return *s.Velocity, true
+}
+
+// GetTrueTrack returns the value of TrueTrack, ok is false if it is nil
+func (s StateVector) GetTrueTrack() (value float64, ok bool) {
+	if s.TrueTrack == nil {
+		return value, false
+	}
+
+	return *s.TrueTrack, true
+}
+
+// GetVerticalRate returns the value of VerticalRate, ok is false if it is nil
+func (s StateVector) GetVerticalRate() (value float64, ok bool) {
+	if s.VerticalRate == nil {
+		return value, false
+	}
+
+	return *s.VerticalRate, true
+}
+
+// GetSensors returns the value of Sensors, ok is false if it is nil
+func (s StateVector) GetSensors() (value []int, ok bool) {
+	if s.Sensors == nil {
+		return value, false
+	}
+
+	return *s.Sensors, true
+}
+
+// GetGeoAltitude returns the value of GeoAltitude, ok is false if it is nil
+func (s StateVector) GetGeoAltitude() (value float64, ok bool) {
+	if s.GeoAltitude == nil {
+		return value, false
+	}
+
+	return *s.GeoAltitude, true
+}
+
+// GetSquawk returns the value of Squawk, ok is false if it is nil
+func (s StateVector) GetSquawk() (value string, ok bool) {
+	if s.Squawk == nil {
+		return value, false
+	}
+
+	return *s.Squawk, true
+}
+
+// GetSpi returns the value of Spi, ok is false if it is nil
+func (s StateVector) GetSpi() (value bool, ok bool) {
+	if s.Spi == nil {
+		return value, false
+	}
+
+	return *s.Spi, true
+}
+
+// GetCategory returns the value of Category, ok is false if it is nil
+func (s StateVector) GetCategory() (value int, ok bool) {
+	if s.Category == nil {
+		return value, false
+	}
+
+	return *s.Category, true
+}
+
+// StateVectorField is a bitmask of the optional fields of a StateVector
+type StateVectorField uint32
+
+// Optional fields which can be present in a StateVectorFlat
+const (
+	StateVectorFieldCallsign StateVectorField = 1 << iota
+	StateVectorFieldTimePosition
+	StateVectorFieldLongitude
+	StateVectorFieldLatitude
+	StateVectorFieldBaroAltitude
+	StateVectorFieldVelocity
+	StateVectorFieldTrueTrack
+	StateVectorFieldVerticalRate
+	StateVectorFieldSensors
+	StateVectorFieldGeoAltitude
+	StateVectorFieldSquawk
+	StateVectorFieldSpi
+	StateVectorFieldCategory
+)
+
+// StateVectorFlat is a value-type view of a StateVector. Nil fields hold the zero value and are not set in Present.
+type StateVectorFlat struct {
+	ICAO24         string
+	Callsign       string
+	OriginCountry  string
+	TimePosition   int64
+	LastContact    int64
+	Longitude      float64
+	Latitude       float64
+	BaroAltitude   float64
+	OnGround       bool
+	Velocity       float64
+	TrueTrack      float64
+	VerticalRate   float64
+	Sensors        []int
+	GeoAltitude    float64
+	Squawk         string
+	Spi            bool
+	PositionSource int
+	Category       int
+	Present        StateVectorField
+}
+
+// Has returns true when all provided fields were present in the StateVector
+func (v StateVectorFlat) Has(fields StateVectorField) bool {
+	return v.Present&fields == fields
+}
+
+// Flat returns a value-type view of the StateVector
+func (s StateVector) Flat() StateVectorFlat {
+	v := StateVectorFlat{
+		ICAO24:         s.ICAO24,
+		OriginCountry:  s.OriginCountry,
+		LastContact:    s.LastContact,
+		OnGround:       s.OnGround,
+		PositionSource: s.PositionSource,
+	}
+
+	if s.Callsign != nil {
+		v.Callsign = *s.Callsign
+		v.Present |= StateVectorFieldCallsign
+	}
+
+	if s.TimePosition != nil {
+		v.TimePosition = *s.TimePosition
+		v.Present |= StateVectorFieldTimePosition
+	}
+
+	if s.Longitude != nil {
+		v.Longitude = *s.Longitude
+		v.Present |= StateVectorFieldLongitude
+	}
+
+	if s.Latitude != nil {
+		v.Latitude = *s.Latitude
+		v.Present |= StateVectorFieldLatitude
+	}
+
+	if s.BaroAltitude != nil {
+		v.BaroAltitude = *s.BaroAltitude
+		v.Present |= StateVectorFieldBaroAltitude
+	}
+
+	if s.Velocity != nil {
+		v.Velocity = *s.Velocity
+		v.Present |= StateVectorFieldVelocity
+	}
+
+	if s.TrueTrack != nil {
+		v.TrueTrack = *s.TrueTrack
+		v.Present |= StateVectorFieldTrueTrack
+	}
+
+	if s.VerticalRate != nil {
+		v.VerticalRate = *s.VerticalRate
+		v.Present |= StateVectorFieldVerticalRate
+	}
+
+	if s.Sensors != nil {
+		v.Sensors = *s.Sensors
+		v.Present |= StateVectorFieldSensors
+	}
+
+	if s.GeoAltitude != nil {
+		v.GeoAltitude = *s.GeoAltitude
+		v.Present |= StateVectorFieldGeoAltitude
+	}
+
+	if s.Squawk != nil {
+		v.Squawk = *s.Squawk
+		v.Present |= StateVectorFieldSquawk
+	}
+
+	if s.Spi != nil {
+		v.Spi = *s.Spi
+		v.Present |= StateVectorFieldSpi
+	}
+
+	if s.Category != nil {
+		v.Category = *s.Category
+		v.Present |= StateVectorFieldCategory
+	}
+
+	return v
+}
+
+// GetEstDepartureAirport returns the value of EstDepartureAirport, ok is false if it is nil
+func (f Flight) GetEstDepartureAirport() (value string, ok bool) {
+	if f.EstDepartureAirport == nil {
+		return value, false
+	}
+
+	return *f.EstDepartureAirport, true
+}
+
+// GetEstArrivalAirport returns the value of EstArrivalAirport, ok is false if it is nil
+func (f Flight) GetEstArrivalAirport() (value string, ok bool) {
+	if f.EstArrivalAirport == nil {
+		return value, false
+	}
+
+	return *f.EstArrivalAirport, true
+}
+
+// GetCallSign returns the value of CallSign, ok is false if it is nil
+func (f Flight) GetCallSign() (value string, ok bool) {
+	if f.CallSign == nil {
+		return value, false
+	}
+
+	return *f.CallSign, true
+}
+
+// GetEstDepartureAirportHorizontalDistance returns the value of EstDepartureAirportHorizontalDistance, ok is false if it is nil
+func (f Flight) GetEstDepartureAirportHorizontalDistance() (value int64, ok bool) {
+	if f.EstDepartureAirportHorizontalDistance == nil {
+		return value, false
+	}
+
+	return *f.EstDepartureAirportHorizontalDistance, true
+}
+
+// GetEstDepartureAirportVerticalDistance returns the value of EstDepartureAirportVerticalDistance, ok is false if it is nil
+func (f Flight) GetEstDepartureAirportVerticalDistance() (value int64, ok bool) {
+	if f.EstDepartureAirportVerticalDistance == nil {
+		return value, false
+	}
+
+	return *f.EstDepartureAirportVerticalDistance, true
+}
+
+// GetEstArrivalAirportHorizontalDistance returns the value of EstArrivalAirportHorizontalDistance, ok is false if it is nil
+func (f Flight) GetEstArrivalAirportHorizontalDistance() (value int64, ok bool) {
+	if f.EstArrivalAirportHorizontalDistance == nil {
+		return value, false
+	}
+
+	return *f.EstArrivalAirportHorizontalDistance, true
+}
+
+// GetEstArrivalAirportVerticalDistance returns the value of EstArrivalAirportVerticalDistance, ok is false if it is nil
+func (f Flight) GetEstArrivalAirportVerticalDistance() (value int64, ok bool) {
+	if f.EstArrivalAirportVerticalDistance == nil {
+		return value, false
+	}
+
+	return *f.EstArrivalAirportVerticalDistance, true
+}
+
+// FlightField is a bitmask of the optional fields of a Flight
+type FlightField uint32
+
+// Optional fields which can be present in a FlightFlat
+const (
+	FlightFieldEstDepartureAirport FlightField = 1 << iota
+	FlightFieldEstArrivalAirport
+	FlightFieldCallSign
+	FlightFieldEstDepartureAirportHorizontalDistance
+	FlightFieldEstDepartureAirportVerticalDistance
+	FlightFieldEstArrivalAirportHorizontalDistance
+	FlightFieldEstArrivalAirportVerticalDistance
+)
+
+// FlightFlat is a value-type view of a Flight. Nil fields hold the zero value and are not set in Present.
+type FlightFlat struct {
+	ICAO24                                string
+	FirstSeen                             int64
+	EstDepartureAirport                   string
+	LastSeen                              int64
+	EstArrivalAirport                     string
+	CallSign                              string
+	EstDepartureAirportHorizontalDistance int64
+	EstDepartureAirportVerticalDistance   int64
+	EstArrivalAirportHorizontalDistance   int64
+	EstArrivalAirportVerticalDistance     int64
+	DepartureAirportCandidatesCount       int64
+	ArrivalAirportCandidatesCount         int64
+	Present                               FlightField
+}
+
+// Has returns true when all provided fields were present in the Flight
+func (v FlightFlat) Has(fields FlightField) bool {
+	return v.Present&fields == fields
+}
+
+// Flat returns a value-type view of the Flight
+func (f Flight) Flat() FlightFlat {
+	v := FlightFlat{
+		ICAO24:                          f.ICAO24,
+		FirstSeen:                       f.FirstSeen,
+		LastSeen:                        f.LastSeen,
+		DepartureAirportCandidatesCount: f.DepartureAirportCandidatesCount,
+		ArrivalAirportCandidatesCount:   f.ArrivalAirportCandidatesCount,
+	}
+
+	if f.EstDepartureAirport != nil {
+		v.EstDepartureAirport = *f.EstDepartureAirport
+		v.Present |= FlightFieldEstDepartureAirport
+	}
+
+	if f.EstArrivalAirport != nil {
+		v.EstArrivalAirport = *f.EstArrivalAirport
+		v.Present |= FlightFieldEstArrivalAirport
+	}
+
+	if f.CallSign != nil {
+		v.CallSign = *f.CallSign
+		v.Present |= FlightFieldCallSign
+	}
+
+	if f.EstDepartureAirportHorizontalDistance != nil {
+		v.EstDepartureAirportHorizontalDistance = *f.EstDepartureAirportHorizontalDistance
+		v.Present |= FlightFieldEstDepartureAirportHorizontalDistance
+	}
+
+	if f.EstDepartureAirportVerticalDistance != nil {
+		v.EstDepartureAirportVerticalDistance = *f.EstDepartureAirportVerticalDistance
+		v.Present |= FlightFieldEstDepartureAirportVerticalDistance
+	}
+
+	if f.EstArrivalAirportHorizontalDistance != nil {
+		v.EstArrivalAirportHorizontalDistance = *f.EstArrivalAirportHorizontalDistance
+		v.Present |= FlightFieldEstArrivalAirportHorizontalDistance
+	}
+
+	if f.EstArrivalAirportVerticalDistance != nil {
+		v.EstArrivalAirportVerticalDistance = *f.EstArrivalAirportVerticalDistance
+		v.Present |= FlightFieldEstArrivalAirportVerticalDistance
+	}
+
+	return v
+}
@@ -0,0 +1,98 @@
+package goflight_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"testing"
+)
+
+func TestStateVector_Getters(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/state_vector.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var vector goflight.StateVector
+
+	if err = json.Unmarshal(data, &vector); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if callsign, ok := vector.GetCallsign(); !ok || callsign != "SKW3609 " {
+		t.Errorf("expected %q to equal %q", callsign, "SKW3609 ")
+	}
+
+	if latitude, ok := vector.GetLatitude(); !ok || latitude != 47.6935 {
+		t.Errorf("expected %v to equal 47.6935", latitude)
+	}
+
+	if sensors, ok := vector.GetSensors(); ok || sensors != nil {
+		t.Errorf("expected sensors to be absent, got %v", sensors)
+	}
+}
+
+func TestStateVector_Flat(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/state_vector_null_values.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var vector goflight.StateVector
+
+	if err = json.Unmarshal(data, &vector); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	flat := vector.Flat()
+
+	if flat.ICAO24 != "a2e5ec" || flat.LastContact != 1586031309 {
+		t.Errorf("expected required fields to be copied, got %+v", flat)
+	}
+
+	if flat.Present != 0 {
+		t.Errorf("expected no optional fields to be present, got %b", flat.Present)
+	}
+
+	if flat.Latitude != 0 || flat.Callsign != "" {
+		t.Errorf("expected absent fields to hold zero values, got %+v", flat)
+	}
+
+	latitude, longitude := 52.0, 4.0
+	vector.Latitude, vector.Longitude = &latitude, &longitude
+	flat = vector.Flat()
+
+	if !flat.Has(goflight.StateVectorFieldLatitude | goflight.StateVectorFieldLongitude) {
+		t.Errorf("expected latitude and longitude to be present, got %b", flat.Present)
+	}
+
+	if flat.Has(goflight.StateVectorFieldLatitude | goflight.StateVectorFieldCallsign) {
+		t.Error("expected callsign to be absent")
+	}
+}
+
+func TestFlight_Flat(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/flights.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var flights []goflight.Flight
+
+	if err = json.Unmarshal(data, &flights); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	flat := flights[0].Flat()
+
+	if !flat.Has(goflight.FlightFieldCallSign) || flat.CallSign != "TAM3533 " {
+		t.Errorf("expected callsign to be present, got %+v", flat)
+	}
+
+	if _, ok := flights[0].GetEstDepartureAirport(); ok || flat.Has(goflight.FlightFieldEstDepartureAirport) {
+		t.Error("expected departure airport to be absent")
+	}
+}
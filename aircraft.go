@@ -0,0 +1,241 @@
+package goflight
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Aircraft holds the details of an aircraft from the Opensky aircraft database
+type Aircraft struct {
+	ICAO24           string // Unique ICAO 24-bit address of the transponder in lower case hex string representation.
+	Registration     string // Registration of the aircraft, e.g. PH-BXA.
+	ManufacturerICAO string // ICAO code of the manufacturer, e.g. BOEING.
+	ManufacturerName string // Name of the manufacturer, e.g. Boeing.
+	Model            string // Model of the aircraft, e.g. 737-8K2.
+	TypeCode         string // ICAO type designator of the aircraft, e.g. B738.
+	Operator         string // Name of the operator.
+	OperatorCallsign string // Radiotelephony callsign of the operator, e.g. KLM.
+	OperatorICAO     string // ICAO designator of the operator, e.g. KLM.
+	Owner            string // Name of the owner.
+}
+
+// aircraftColumns are the columns of the Opensky aircraft database read by LoadAircraftDatabase
+var aircraftColumns = []string{
+	"icao24",
+	"registration",
+	"manufacturericao",
+	"manufacturername",
+	"model",
+	"typecode",
+	"operator",
+	"operatorcallsign",
+	"operatoricao",
+	"owner",
+}
+
+// AircraftDatabase holds a set of aircraft indexed by ICAO 24-bit address
+type AircraftDatabase struct {
+	aircraft map[string]Aircraft
+}
+
+// NewAircraftDatabase creates a database of the aircraft
+func NewAircraftDatabase(aircraft []Aircraft) *AircraftDatabase {
+	db := &AircraftDatabase{aircraft: make(map[string]Aircraft, len(aircraft))}
+
+	for _, a := range aircraft {
+		a.ICAO24 = strings.ToLower(strings.TrimSpace(a.ICAO24))
+		db.aircraft[a.ICAO24] = a
+	}
+
+	return db
+}
+
+// LoadAircraftDatabase reads the Opensky aircraft database CSV file, available at
+// https://opensky-network.org/datasets/metadata/. Both the older files with double quoted values and the newer
+// files with single quoted values are supported. Columns are matched by name, unknown columns are ignored and
+// rows without an ICAO 24-bit address are skipped.
+func LoadAircraftDatabase(r io.Reader) (*AircraftDatabase, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	header, err := splitAircraftRecord(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+
+	if err != nil {
+		return nil, &CSVParseError{Line: 1, Err: err}
+	}
+
+	for i := range header {
+		header[i] = strings.ToLower(header[i])
+	}
+
+	indexes := csvColumnIndexes(header, len(aircraftColumns), func(i int) string { return aircraftColumns[i] })
+
+	if indexes[0] < 0 {
+		return nil, &CSVParseError{Line: 1, Column: aircraftColumns[0], Err: ErrMissingColumn}
+	}
+
+	var aircraft []Aircraft
+	line := 1
+
+	for scanner.Scan() {
+		line++
+
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		record, err := splitAircraftRecord(scanner.Text())
+
+		if err != nil {
+			return nil, &CSVParseError{Line: line, Err: err}
+		}
+
+		value := func(i int) string {
+			if indexes[i] < 0 || indexes[i] >= len(record) {
+				return ""
+			}
+
+			return strings.TrimSpace(record[indexes[i]])
+		}
+
+		a := Aircraft{
+			ICAO24:           value(0),
+			Registration:     value(1),
+			ManufacturerICAO: value(2),
+			ManufacturerName: value(3),
+			Model:            value(4),
+			TypeCode:         value(5),
+			Operator:         value(6),
+			OperatorCallsign: value(7),
+			OperatorICAO:     value(8),
+			Owner:            value(9),
+		}
+
+		if a.ICAO24 != "" {
+			aircraft = append(aircraft, a)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewAircraftDatabase(aircraft), nil
+}
+
+// splitAircraftRecord splits a line of the aircraft database into its values. A value can be quoted with either
+// single or double quotes, a quote inside a quoted value is escaped by doubling it.
+func splitAircraftRecord(line string) ([]string, error) {
+	var record []string
+	var value strings.Builder
+	i := 0
+
+	for {
+		value.Reset()
+
+		if i < len(line) && (line[i] == '\'' || line[i] == '"') {
+			quote := line[i]
+			i++
+
+			for {
+				if i >= len(line) {
+					return nil, fmt.Errorf("unterminated quoted value %q", line)
+				}
+
+				if line[i] == quote {
+					if i+1 < len(line) && line[i+1] == quote {
+						value.WriteByte(quote)
+						i += 2
+						continue
+					}
+
+					i++
+					break
+				}
+
+				value.WriteByte(line[i])
+				i++
+			}
+
+			if i < len(line) && line[i] != ',' {
+				return nil, fmt.Errorf("unexpected %q after quoted value", line[i])
+			}
+		} else {
+			end := strings.IndexByte(line[i:], ',')
+
+			if end < 0 {
+				end = len(line) - i
+			}
+
+			value.WriteString(line[i : i+end])
+			i += end
+		}
+
+		record = append(record, value.String())
+
+		if i >= len(line) {
+			return record, nil
+		}
+
+		// Skip the comma
+		i++
+	}
+}
+
+// Len returns the number of aircraft in the database
+func (db *AircraftDatabase) Len() int {
+	return len(db.aircraft)
+}
+
+// Lookup returns the aircraft with the ICAO 24-bit address, the address is matched case insensitively
+func (db *AircraftDatabase) Lookup(icao24 string) (Aircraft, bool) {
+	a, ok := db.aircraft[strings.ToLower(strings.TrimSpace(icao24))]
+
+	return a, ok
+}
+
+// EnrichedStateVector is a StateVector with the details of its aircraft
+type EnrichedStateVector struct {
+	StateVector
+	Aircraft *Aircraft // Details of the aircraft, nil if it is not in the database.
+	Military bool      // Whether the ICAO 24-bit address is in a range known to be used by military aircraft.
+}
+
+// Enrich looks up the aircraft of the state vector. The origin country is inferred from the ICAO 24-bit address
+// when it is empty, like in the historical dumps.
+func (db *AircraftDatabase) Enrich(s StateVector) EnrichedStateVector {
+	enriched := EnrichedStateVector{StateVector: s, Military: IsMilitaryICAO24(s.ICAO24)}
+
+	if db != nil {
+		if a, ok := db.Lookup(s.ICAO24); ok {
+			enriched.Aircraft = &a
+		}
+	}
+
+	if enriched.OriginCountry == "" {
+		enriched.OriginCountry, _ = ICAO24Country(s.ICAO24)
+	}
+
+	return enriched
+}
+
+// EnrichStates looks up the aircraft of every state vector
+func (db *AircraftDatabase) EnrichStates(states []StateVector) []EnrichedStateVector {
+	enriched := make([]EnrichedStateVector, len(states))
+
+	for i, s := range states {
+		enriched[i] = db.Enrich(s)
+	}
+
+	return enriched
+}
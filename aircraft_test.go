@@ -0,0 +1,130 @@
+package goflight_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"os"
+	"strings"
+	"testing"
+)
+
+func readMockAircraftDatabase(t *testing.T) *goflight.AircraftDatabase {
+	f, err := os.Open("./mocks/aircraft_database.csv")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defer f.Close()
+	db, err := goflight.LoadAircraftDatabase(f)
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return db
+}
+
+func TestLoadAircraftDatabase(t *testing.T) {
+	db := readMockAircraftDatabase(t)
+
+	if db.Len() != 3 {
+		t.Errorf("expected %v aircraft to equal 3", db.Len())
+	}
+
+	a, ok := db.Lookup("A2E5EC")
+	expected := goflight.Aircraft{
+		ICAO24:           "a2e5ec",
+		Registration:     "N286WN",
+		ManufacturerICAO: "BOEING",
+		ManufacturerName: "Boeing",
+		Model:            "737-8H4",
+		TypeCode:         "B738",
+		Operator:         "Southwest Airlines",
+		OperatorCallsign: "SOUTHWEST",
+		OperatorICAO:     "SWA",
+		Owner:            "Wells Fargo Trust Company, NA",
+	}
+
+	if !ok || a != expected {
+		t.Errorf("expected %+v to equal %+v", a, expected)
+	}
+
+	if _, ok = db.Lookup("000000"); ok {
+		t.Errorf("expected no aircraft for an unknown address")
+	}
+}
+
+func TestLoadAircraftDatabase_DoubleQuotes(t *testing.T) {
+	data := `"icao24","registration","manufacturericao","manufacturername","model","typecode","serialnumber","operator","operatorcallsign","operatoricao","owner"
+"484AC1","PH-EXB","EMBRAER","Embraer","ERJ 190-100 STD","E190","19000700","KLM Cityhopper","CITY","KLC","KLM ""Cityhopper"" B.V."
+`
+	db, err := goflight.LoadAircraftDatabase(strings.NewReader(data))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	a, ok := db.Lookup("484ac1")
+
+	if !ok || a.Registration != "PH-EXB" || a.TypeCode != "E190" || a.Owner != `KLM "Cityhopper" B.V.` {
+		t.Errorf("unexpected aircraft: %+v", a)
+	}
+}
+
+func TestLoadAircraftDatabase_Error(t *testing.T) {
+	tests := map[string]string{
+		"empty":          "",
+		"missing icao24": "registration,typecode\nPH-EXB,E190\n",
+		"unterminated":   "icao24,registration\n'484ac1,PH-EXB\n",
+		"after quote":    "icao24,registration\n'484ac1'x,PH-EXB\n",
+	}
+
+	for label, data := range tests {
+		t.Run(label, func(t *testing.T) {
+			if _, err := goflight.LoadAircraftDatabase(strings.NewReader(data)); err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+
+	_, err := goflight.LoadAircraftDatabase(strings.NewReader(tests["missing icao24"]))
+
+	if !errors.Is(err, goflight.ErrMissingColumn) {
+		t.Errorf("expected %v to be %v", err, goflight.ErrMissingColumn)
+	}
+}
+
+func TestAircraftDatabase_Enrich(t *testing.T) {
+	db := readMockAircraftDatabase(t)
+
+	enriched := db.Enrich(goflight.StateVector{ICAO24: "484ac1"})
+
+	if enriched.Aircraft == nil || enriched.Aircraft.Registration != "PH-EXB" || enriched.Military {
+		t.Errorf("unexpected enriched state vector: %+v", enriched)
+	}
+
+	if enriched.OriginCountry != "Kingdom of the Netherlands" {
+		t.Errorf("expected origin country %q to be inferred", enriched.OriginCountry)
+	}
+
+	enriched = db.Enrich(goflight.StateVector{ICAO24: "ae1460", OriginCountry: "Testland"})
+
+	if enriched.Aircraft == nil || enriched.Aircraft.TypeCode != "C30J" || !enriched.Military || enriched.OriginCountry != "Testland" {
+		t.Errorf("unexpected enriched state vector: %+v", enriched)
+	}
+
+	states := db.EnrichStates(readMockStates(t))
+
+	for _, s := range states {
+		if _, ok := db.Lookup(s.ICAO24); ok != (s.Aircraft != nil) || ok && s.Aircraft.ICAO24 != s.ICAO24 {
+			t.Errorf("unexpected aircraft for %v: %+v", s.ICAO24, s.Aircraft)
+		}
+	}
+
+	var empty *goflight.AircraftDatabase
+
+	if enriched = empty.Enrich(goflight.StateVector{ICAO24: "484ac1"}); enriched.Aircraft != nil || enriched.OriginCountry == "" {
+		t.Errorf("unexpected enriched state vector: %+v", enriched)
+	}
+}
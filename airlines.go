@@ -0,0 +1,152 @@
+package goflight
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Airline is an aircraft operator with an ICAO designator
+type Airline struct {
+	ICAO     string // Three letter ICAO designator, e.g. KLM.
+	IATA     string // Two character IATA code, e.g. KL. Empty if the operator has none.
+	Name     string
+	Callsign string // Radiotelephony callsign, e.g. SPEEDBIRD for BAW.
+	Country  string // ISO 3166-1 alpha-2 country code.
+	Military bool   // Whether the designator is used by a military operator.
+}
+
+// airlineColumns are the columns of the airline CSV format read by LoadAirlines
+var airlineColumns = []string{"icao", "iata", "name", "callsign", "country", "military"}
+
+// AirlineDatabase holds a set of airlines indexed by code
+type AirlineDatabase struct {
+	airlines []Airline
+	byCode   map[string]int
+}
+
+// NewAirlineDatabase creates a database of the airlines
+func NewAirlineDatabase(airlines []Airline) *AirlineDatabase {
+	db := &AirlineDatabase{airlines: airlines, byCode: make(map[string]int, 2*len(airlines))}
+
+	for i, a := range airlines {
+		db.byCode[strings.ToUpper(a.ICAO)] = i
+
+		// IATA codes are reused by multiple operators, the first one wins
+		if code := strings.ToUpper(a.IATA); code != "" {
+			if _, ok := db.byCode[code]; !ok {
+				db.byCode[code] = i
+			}
+		}
+	}
+
+	return db
+}
+
+// LoadAirlines reads airlines from a CSV file with the columns icao, iata, name, callsign, country and military,
+// like the embedded dataset
+func LoadAirlines(r io.Reader) (*AirlineDatabase, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := csvColumnIndexes(header, len(airlineColumns), func(i int) string { return airlineColumns[i] })
+	var airlines []Airline
+	line := 1
+
+	for {
+		record, err := reader.Read()
+
+		if err == io.EOF {
+			return NewAirlineDatabase(airlines), nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		line++
+		value := func(i int) string {
+			if indexes[i] < 0 || indexes[i] >= len(record) {
+				return ""
+			}
+
+			return strings.TrimSpace(record[indexes[i]])
+		}
+
+		airline := Airline{ICAO: value(0), IATA: value(1), Name: value(2), Callsign: value(3), Country: value(4)}
+
+		if military := value(5); military != "" {
+			if airline.Military, err = strconv.ParseBool(military); err != nil {
+				return nil, &CSVParseError{Line: line, Column: airlineColumns[5], Err: err}
+			}
+		}
+
+		airlines = append(airlines, airline)
+	}
+}
+
+// Airlines returns all airlines in the database
+func (db *AirlineDatabase) Airlines() []Airline {
+	airlines := make([]Airline, len(db.airlines))
+	copy(airlines, db.airlines)
+
+	return airlines
+}
+
+// Lookup returns the airline with the ICAO designator or IATA code, the code is matched case insensitively
+func (db *AirlineDatabase) Lookup(code string) (Airline, bool) {
+	i, ok := db.byCode[strings.ToUpper(strings.TrimSpace(code))]
+
+	if !ok {
+		return Airline{}, false
+	}
+
+	return db.airlines[i], true
+}
+
+var defaultAirlines struct {
+	sync.RWMutex
+	once sync.Once
+	db   *AirlineDatabase
+}
+
+// DefaultAirlines returns the airline database used by ParseCallsign. It is the embedded dataset of major
+// airlines and military operators, generated from data/airlines.csv.
+func DefaultAirlines() *AirlineDatabase {
+	defaultAirlines.once.Do(func() {
+		db, err := LoadAirlines(strings.NewReader(embeddedAirlines))
+
+		if err != nil {
+			panic("goflight: invalid embedded airline data: " + err.Error())
+		}
+
+		defaultAirlines.Lock()
+		defer defaultAirlines.Unlock()
+		defaultAirlines.db = db
+	})
+
+	defaultAirlines.RLock()
+	defer defaultAirlines.RUnlock()
+
+	return defaultAirlines.db
+}
+
+// SetDefaultAirlines replaces the airline database used by ParseCallsign, e.g. with a complete dataset read
+// by LoadAirlines
+func SetDefaultAirlines(db *AirlineDatabase) {
+	defaultAirlines.once.Do(func() {})
+	defaultAirlines.Lock()
+	defer defaultAirlines.Unlock()
+	defaultAirlines.db = db
+}
+
+// LookupAirline returns the airline with the ICAO designator or IATA code from the default database
+func LookupAirline(code string) (Airline, bool) {
+	return DefaultAirlines().Lookup(code)
+}
@@ -0,0 +1,409 @@
+package goflight
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/marcelblijleven/goflight/units"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Runway is a runway direction of an airport
+type Runway struct {
+	Name    string  // Designator of the runway direction without parallel runway suffix, e.g. 18.
+	Heading float64 // True heading of the runway direction in decimal degrees.
+}
+
+// Airport represents an airport and its runways
+type Airport struct {
+	ICAO      string   // ICAO code of the airport.
+	IATA      string   // IATA code of the airport, empty if it has none.
+	Name      string   // Name of the airport.
+	Country   string   // ISO 3166-1 alpha-2 code of the country of the airport.
+	Position  Point    // Position of the airport reference point.
+	Elevation float64  // Elevation of the airport in meters.
+	Runways   []Runway // Runway directions of the airport, parallel runways are listed once.
+}
+
+// RunwayForHeading returns the runway direction closest to the heading within maxDeviation degrees,
+// ok is false when there is none
+func (a Airport) RunwayForHeading(heading, maxDeviation float64) (runway Runway, ok bool) {
+	best := maxDeviation
+
+	for _, r := range a.Runways {
+		if d := math.Abs(math.Remainder(heading-r.Heading, 360)); d <= best {
+			runway, best, ok = r, d, true
+		}
+	}
+
+	return runway, ok
+}
+
+// airportColumns are the columns of the airport CSV format read by LoadAirports
+var airportColumns = []string{"icao", "iata", "name", "country", "latitude", "longitude", "elevation_ft", "runways"}
+
+// airportCellSize is the size in degrees of the grid cells used to find airports near a position
+const airportCellSize = 1.0
+
+type airportCell struct {
+	latitude, longitude int
+}
+
+// AirportDatabase holds a set of airports and indexes them by code and position
+type AirportDatabase struct {
+	airports []Airport
+	byCode   map[string]int
+	cells    map[airportCell][]int
+}
+
+// NewAirportDatabase creates a database of the airports
+func NewAirportDatabase(airports []Airport) *AirportDatabase {
+	db := &AirportDatabase{
+		airports: airports,
+		byCode:   make(map[string]int, 2*len(airports)),
+		cells:    make(map[airportCell][]int),
+	}
+
+	for i, a := range airports {
+		db.byCode[strings.ToUpper(a.ICAO)] = i
+
+		if a.IATA != "" {
+			db.byCode[strings.ToUpper(a.IATA)] = i
+		}
+
+		cell := cellOf(a.Position)
+		db.cells[cell] = append(db.cells[cell], i)
+	}
+
+	return db
+}
+
+// LoadAirports reads airports from a CSV file with the columns icao, iata, name, country, latitude, longitude,
+// elevation_ft and runways, like the embedded dataset. The runways are separated by semicolons and written as
+// the designators of both directions with the true heading of the first direction, e.g. 18/36:183.
+func LoadAirports(r io.Reader) (*AirportDatabase, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := csvColumnIndexes(header, len(airportColumns), func(i int) string { return airportColumns[i] })
+	var airports []Airport
+	line := 1
+
+	for {
+		record, err := reader.Read()
+
+		if err == io.EOF {
+			return NewAirportDatabase(airports), nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		line++
+		airport, err := parseAirport(record, indexes, line)
+
+		if err != nil {
+			return nil, err
+		}
+
+		airports = append(airports, airport)
+	}
+}
+
+func parseAirport(record []string, indexes []int, line int) (Airport, error) {
+	value := func(i int) string {
+		if indexes[i] < 0 || indexes[i] >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[indexes[i]])
+	}
+
+	airport := Airport{ICAO: value(0), IATA: value(1), Name: value(2), Country: value(3)}
+	var err error
+
+	if airport.Position.Latitude, err = strconv.ParseFloat(value(4), 64); err != nil {
+		return Airport{}, &CSVParseError{Line: line, Column: airportColumns[4], Err: err}
+	}
+
+	if airport.Position.Longitude, err = strconv.ParseFloat(value(5), 64); err != nil {
+		return Airport{}, &CSVParseError{Line: line, Column: airportColumns[5], Err: err}
+	}
+
+	if elevation := value(6); elevation != "" {
+		feet, err := strconv.ParseFloat(elevation, 64)
+
+		if err != nil {
+			return Airport{}, &CSVParseError{Line: line, Column: airportColumns[6], Err: err}
+		}
+
+		airport.Elevation = units.FeetToMeters(feet)
+	}
+
+	if airport.Runways, err = parseRunways(value(7)); err != nil {
+		return Airport{}, &CSVParseError{Line: line, Column: airportColumns[7], Err: err}
+	}
+
+	return airport, nil
+}
+
+// parseRunways parses runways like 04/22:41;18/36:183 into both directions of every runway
+func parseRunways(value string) ([]Runway, error) {
+	var runways []Runway
+
+	for _, runway := range strings.Split(value, ";") {
+		if runway == "" {
+			continue
+		}
+
+		parts := strings.Split(runway, ":")
+
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid runway %q", runway)
+		}
+
+		heading, err := strconv.ParseFloat(parts[1], 64)
+
+		if err != nil {
+			return nil, err
+		}
+
+		names := strings.Split(parts[0], "/")
+		runways = append(runways, Runway{Name: names[0], Heading: heading})
+
+		if len(names) == 2 {
+			runways = append(runways, Runway{Name: names[1], Heading: math.Mod(heading+180, 360)})
+		}
+	}
+
+	return runways, nil
+}
+
+func cellOf(p Point) airportCell {
+	return airportCell{
+		latitude:  int(math.Floor(p.Latitude / airportCellSize)),
+		longitude: int(math.Floor(p.Longitude / airportCellSize)),
+	}
+}
+
+// Airports returns all airports in the database
+func (db *AirportDatabase) Airports() []Airport {
+	airports := make([]Airport, len(db.airports))
+	copy(airports, db.airports)
+
+	return airports
+}
+
+// Lookup returns the airport with the ICAO or IATA code, ignoring case
+func (db *AirportDatabase) Lookup(code string) (Airport, bool) {
+	i, ok := db.byCode[strings.ToUpper(strings.TrimSpace(code))]
+
+	if !ok {
+		return Airport{}, false
+	}
+
+	return db.airports[i], true
+}
+
+// Within returns the airports within radius meters of p, ordered by distance
+func (db *AirportDatabase) Within(p Point, radius float64) []Airport {
+	type candidate struct {
+		index    int
+		distance float64
+	}
+
+	var candidates []candidate
+
+	db.candidates(p, radius, func(i int) {
+		if d := Distance(p, db.airports[i].Position); d <= radius {
+			candidates = append(candidates, candidate{i, d})
+		}
+	})
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	airports := make([]Airport, len(candidates))
+
+	for i, c := range candidates {
+		airports[i] = db.airports[c.index]
+	}
+
+	return airports
+}
+
+// Nearest returns the airport closest to p within maxDistance meters, ok is false when there is none
+func (db *AirportDatabase) Nearest(p Point, maxDistance float64) (airport Airport, distance float64, ok bool) {
+	index := -1
+	distance = maxDistance
+
+	db.candidates(p, maxDistance, func(i int) {
+		if d := Distance(p, db.airports[i].Position); d < distance || (d == distance && index < 0) {
+			index, distance = i, d
+		}
+	})
+
+	if index < 0 {
+		return Airport{}, maxDistance, false
+	}
+
+	return db.airports[index], distance, true
+}
+
+// candidates calls fn with the index of every airport in a grid cell within radius meters of p
+func (db *AirportDatabase) candidates(p Point, radius float64, fn func(i int)) {
+	metersPerDegree := earthRadius * math.Pi / 180
+	latitudeCells := int(math.Ceil(radius / metersPerDegree / airportCellSize))
+	maxLatitude := math.Min(89.9, math.Abs(p.Latitude)+float64(latitudeCells)*airportCellSize)
+	longitudeCells := int(math.Ceil(radius / (metersPerDegree * math.Cos(radians(maxLatitude))) / airportCellSize))
+
+	// Fall back to all airports when the search area covers a large part of the world
+	if latitudeCells*longitudeCells*4 >= len(db.cells) || longitudeCells >= int(180/airportCellSize) {
+		for i := range db.airports {
+			fn(i)
+		}
+
+		return
+	}
+
+	center := cellOf(p)
+	wrap := int(360 / airportCellSize)
+
+	for lat := center.latitude - latitudeCells; lat <= center.latitude+latitudeCells; lat++ {
+		for lon := center.longitude - longitudeCells; lon <= center.longitude+longitudeCells; lon++ {
+			// Wrap longitude cells around the antimeridian
+			normalized := ((lon+wrap/2)%wrap+wrap)%wrap - wrap/2
+
+			for _, i := range db.cells[airportCell{lat, normalized}] {
+				fn(i)
+			}
+		}
+	}
+}
+
+// EnrichedFlight is a Flight with the details of its estimated departure and arrival airport
+type EnrichedFlight struct {
+	Flight
+	DepartureAirport *Airport // Estimated departure airport, nil if unknown or not in the database.
+	ArrivalAirport   *Airport // Estimated arrival airport, nil if unknown or not in the database.
+}
+
+// Enrich looks up the estimated departure and arrival airport of the flight
+func (db *AirportDatabase) Enrich(f Flight) EnrichedFlight {
+	enriched := EnrichedFlight{Flight: f}
+
+	if f.EstDepartureAirport != nil {
+		if airport, ok := db.Lookup(*f.EstDepartureAirport); ok {
+			enriched.DepartureAirport = &airport
+		}
+	}
+
+	if f.EstArrivalAirport != nil {
+		if airport, ok := db.Lookup(*f.EstArrivalAirport); ok {
+			enriched.ArrivalAirport = &airport
+		}
+	}
+
+	return enriched
+}
+
+var defaultAirports struct {
+	sync.RWMutex
+	once sync.Once
+	db   *AirportDatabase
+}
+
+// DefaultAirports returns the airport database used by the package level airport functions. It is the
+// embedded dataset of major airports, generated from data/airports.csv.
+func DefaultAirports() *AirportDatabase {
+	defaultAirports.once.Do(func() {
+		db, err := LoadAirports(strings.NewReader(embeddedAirports))
+
+		if err != nil {
+			panic("goflight: invalid embedded airport data: " + err.Error())
+		}
+
+		defaultAirports.Lock()
+		defer defaultAirports.Unlock()
+		defaultAirports.db = db
+	})
+
+	defaultAirports.RLock()
+	defer defaultAirports.RUnlock()
+
+	return defaultAirports.db
+}
+
+// SetDefaultAirports replaces the airport database used by the package level airport functions,
+// e.g. with a complete dataset read by LoadAirports
+func SetDefaultAirports(db *AirportDatabase) {
+	defaultAirports.once.Do(func() {})
+	defaultAirports.Lock()
+	defer defaultAirports.Unlock()
+	defaultAirports.db = db
+}
+
+// LookupAirport returns the airport with the ICAO or IATA code from the default database
+func LookupAirport(code string) (Airport, bool) {
+	return DefaultAirports().Lookup(code)
+}
+
+// NearestAirport returns the airport of the default database closest to p within maxDistance meters,
+// ok is false when there is none
+func NearestAirport(p Point, maxDistance float64) (airport Airport, distance float64, ok bool) {
+	return DefaultAirports().Nearest(p, maxDistance)
+}
+
+// EnrichFlights looks up the estimated departure and arrival airports of the flights in the default database
+func EnrichFlights(flights []Flight) []EnrichedFlight {
+	db := DefaultAirports()
+	enriched := make([]EnrichedFlight, len(flights))
+
+	for i, f := range flights {
+		enriched[i] = db.Enrich(f)
+	}
+
+	return enriched
+}
+
+// GeoJSON returns a LineString feature from the estimated departure airport to the estimated arrival airport of
+// the flight, looked up in the default airport database. The properties are the flight. ok is false when either
+// airport is unknown.
+func (f Flight) GeoJSON() (feature GeoJSONFeature, ok bool) {
+	return DefaultAirports().FlightGeoJSON(f)
+}
+
+// FlightGeoJSON returns a LineString feature from the estimated departure airport to the estimated arrival
+// airport of the flight, looked up in the database. The properties are the flight. ok is false when either
+// airport is unknown.
+func (db *AirportDatabase) FlightGeoJSON(f Flight) (feature GeoJSONFeature, ok bool) {
+	enriched := db.Enrich(f)
+
+	if enriched.DepartureAirport == nil || enriched.ArrivalAirport == nil {
+		return GeoJSONFeature{}, false
+	}
+
+	return f.GeoJSONBetween(enriched.DepartureAirport.Position, enriched.ArrivalAirport.Position), true
+}
+
+// FlightsGeoJSON returns a feature collection with a LineString feature for every flight of which the estimated
+// departure and arrival airport are in the default airport database
+func FlightsGeoJSON(flights []Flight) GeoJSONFeatureCollection {
+	db := DefaultAirports()
+	features := make([]GeoJSONFeature, 0, len(flights))
+
+	for _, flight := range flights {
+		if feature, ok := db.FlightGeoJSON(flight); ok {
+			features = append(features, feature)
+		}
+	}
+
+	return NewGeoJSONFeatureCollection(features)
+}
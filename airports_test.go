@@ -0,0 +1,217 @@
+package goflight_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const testAirports = `icao,iata,name,country,latitude,longitude,elevation_ft,runways
+NZCI,CHT,Chatham Islands Airport,NZ,-43.81,-176.46,43,04/22:52
+NFFN,NAN,Nadi International Airport,FJ,-17.7554,177.4433,59,02/20:20;09/27:
+AAAA,,"Test, Airport",XX,10,179.99,,
+`
+
+func TestLoadAirports(t *testing.T) {
+	db, err := goflight.LoadAirports(strings.NewReader(strings.Replace(testAirports, "09/27:\n", "09/27:88\n", 1)))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	airport, ok := db.Lookup("cht")
+
+	if !ok || airport.ICAO != "NZCI" || airport.Country != "NZ" || airport.Position.Longitude != -176.46 {
+		t.Fatalf("unexpected airport: %+v", airport)
+	}
+
+	if airport.Elevation < 13.1 || airport.Elevation > 13.2 {
+		t.Errorf("expected elevation %v to be 43 ft in meters", airport.Elevation)
+	}
+
+	if expected := []goflight.Runway{{"04", 52}, {"22", 232}}; !reflect.DeepEqual(airport.Runways, expected) {
+		t.Errorf("expected runways %+v to equal %+v", airport.Runways, expected)
+	}
+
+	if airport, ok = db.Lookup("AAAA"); !ok || airport.Name != "Test, Airport" || airport.IATA != "" || airport.Runways != nil {
+		t.Errorf("unexpected airport: %+v", airport)
+	}
+
+	if _, ok = db.Lookup(""); ok {
+		t.Errorf("expected no airport for an empty code")
+	}
+
+	if len(db.Airports()) != 3 {
+		t.Errorf("expected %v airports to equal 3", len(db.Airports()))
+	}
+}
+
+func TestLoadAirports_Error(t *testing.T) {
+	_, err := goflight.LoadAirports(strings.NewReader(testAirports))
+	var parseErr *goflight.CSVParseError
+
+	if !errors.As(err, &parseErr) || parseErr.Line != 3 || parseErr.Column != "runways" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAirportDatabase_Nearest(t *testing.T) {
+	db, err := goflight.LoadAirports(strings.NewReader(strings.Replace(testAirports, "09/27:\n", "09/27:88\n", 1)))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// The search crosses the antimeridian
+	p := goflight.Point{Latitude: 10, Longitude: -179.9}
+
+	if airport, distance, ok := db.Nearest(p, 50000); !ok || airport.ICAO != "AAAA" || distance > 15000 {
+		t.Errorf("unexpected airport %v at %v m", airport.ICAO, distance)
+	}
+
+	if _, _, ok := db.Nearest(p, 1000); ok {
+		t.Errorf("expected no airport within 1 km")
+	}
+
+	var codes []string
+
+	for _, a := range db.Within(goflight.Point{Latitude: -30, Longitude: 180}, 3000000) {
+		codes = append(codes, a.ICAO)
+	}
+
+	if !reflect.DeepEqual(codes, []string{"NFFN", "NZCI"}) {
+		t.Errorf("unexpected airports: %v", codes)
+	}
+}
+
+// TestEmbeddedAirports fails when data.go wasn't generated again after a change to data/airports.csv
+func TestEmbeddedAirports(t *testing.T) {
+	data, err := ioutil.ReadFile("./data/airports.csv")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if string(data) != goflight.EmbeddedAirports {
+		t.Error("expected the generated airport dataset to equal data/airports.csv, run go generate")
+	}
+}
+
+func TestDefaultAirports(t *testing.T) {
+	for _, code := range []string{"EHAM", "ams", "KJFK", "OTHH"} {
+		if _, ok := goflight.LookupAirport(code); !ok {
+			t.Errorf("expected airport %v to be in the embedded dataset", code)
+		}
+	}
+
+	for _, a := range goflight.DefaultAirports().Airports() {
+		if len(a.ICAO) != 4 || len(a.IATA) != 3 || len(a.Country) != 2 || len(a.Runways)%2 != 0 {
+			t.Errorf("unexpected airport: %+v", a)
+		}
+	}
+}
+
+func TestEnrichFlights(t *testing.T) {
+	departure, arrival := "EHAM", "XXXX"
+	flights := goflight.EnrichFlights([]goflight.Flight{{ICAO24: "484ac1", EstDepartureAirport: &departure, EstArrivalAirport: &arrival}, {ICAO24: "4846e1"}})
+
+	if flights[0].DepartureAirport == nil || flights[0].DepartureAirport.IATA != "AMS" || flights[0].ArrivalAirport != nil {
+		t.Errorf("unexpected airports: %+v", flights[0])
+	}
+
+	if flights[1].ICAO24 != "4846e1" || flights[1].DepartureAirport != nil || flights[1].ArrivalAirport != nil {
+		t.Errorf("unexpected airports: %+v", flights[1])
+	}
+}
+
+func TestSetDefaultAirports(t *testing.T) {
+	previous := goflight.DefaultAirports()
+	defer goflight.SetDefaultAirports(previous)
+
+	goflight.SetDefaultAirports(goflight.NewAirportDatabase([]goflight.Airport{{ICAO: "TEST", Position: goflight.Point{Latitude: 1, Longitude: 1}}}))
+
+	if airport, _, ok := goflight.NearestAirport(goflight.Point{Latitude: 1, Longitude: 1.01}, 5000); !ok || airport.ICAO != "TEST" {
+		t.Errorf("unexpected airport: %+v", airport)
+	}
+}
+
+func TestAirportDatabase_Within_Antimeridian(t *testing.T) {
+	// An airport in the center of every grid cell along the equator
+	var airports []goflight.Airport
+
+	for lon := -180; lon < 180; lon++ {
+		airports = append(airports, goflight.Airport{ICAO: strconv.Itoa(lon), Position: goflight.Point{Latitude: 0.5, Longitude: float64(lon) + 0.5}})
+	}
+
+	var codes []string
+
+	for _, a := range goflight.NewAirportDatabase(airports).Within(goflight.Point{Latitude: 0.5, Longitude: -179.9}, 80000) {
+		codes = append(codes, a.ICAO)
+	}
+
+	if !reflect.DeepEqual(codes, []string{"-180", "179"}) {
+		t.Errorf("unexpected airports: %v", codes)
+	}
+}
+
+func TestFlightsGeoJSON(t *testing.T) {
+	data := `icao,iata,name,country,latitude,longitude,elevation_ft,runways
+EHAM,AMS,Amsterdam Airport Schiphol,NL,52.3086,4.7639,-11,
+EGLL,LHR,London Heathrow Airport,GB,51.4706,-0.4619,83,
+`
+	db, err := goflight.LoadAirports(strings.NewReader(data))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	departure, arrival, unknown := "EHAM", "EGLL", "ZZZZ"
+	flight := goflight.Flight{ICAO24: "484ac1", EstDepartureAirport: &departure, EstArrivalAirport: &arrival, FirstSeen: 100}
+	feature, ok := db.FlightGeoJSON(flight)
+
+	if !ok || feature.Geometry.Type != "LineString" || feature.ID != "484ac1" {
+		t.Fatalf("unexpected feature: %+v", feature)
+	}
+
+	expected := [][]float64{{4.7639, 52.3086}, {-0.4619, 51.4706}}
+
+	if coordinates := feature.Geometry.Coordinates.([][]float64); !reflect.DeepEqual(coordinates, expected) {
+		t.Errorf("expected %v to equal %v", coordinates, expected)
+	}
+
+	if properties := feature.Properties.(goflight.Flight); properties.FirstSeen != 100 {
+		t.Errorf("expected the flight as properties: %+v", properties)
+	}
+
+	flight.EstArrivalAirport = &unknown
+
+	if _, ok = db.FlightGeoJSON(flight); ok {
+		t.Error("expected a flight with an unknown airport to have no feature")
+	}
+
+	flight.EstArrivalAirport = nil
+
+	if _, ok = db.FlightGeoJSON(flight); ok {
+		t.Error("expected a flight without arrival airport to have no feature")
+	}
+
+	if collection := goflight.FlightsGeoJSON(nil); collection.Features == nil {
+		t.Error("expected features to be an empty array")
+	}
+}
+
+func TestFlightsGeoJSON_Default(t *testing.T) {
+	departure, arrival := "EHAM", "EGLL"
+	collection := goflight.FlightsGeoJSON([]goflight.Flight{
+		{ICAO24: "484ac1", EstDepartureAirport: &departure, EstArrivalAirport: &arrival},
+		{ICAO24: "4846e1", EstDepartureAirport: &departure},
+	})
+
+	if len(collection.Features) != 1 || collection.Features[0].ID != "484ac1" {
+		t.Errorf("unexpected features: %+v", collection.Features)
+	}
+}
@@ -0,0 +1,441 @@
+package goflight
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBoardWindow is the period before now shown on an AirportBoard when no window is set
+const DefaultBoardWindow = 3 * time.Hour
+
+// DefaultBoardInterval is the refresh interval of an AirportBoard which requests the states around the airport.
+// A request of a bounding box around an airport costs a single credit of the Opensky API.
+const DefaultBoardInterval = time.Minute
+
+// DefaultBoardWorldwideInterval is the refresh interval of an AirportBoard which requests all states, because
+// the area around the airport can't be requested as a bounding box. A request of all states costs four credits
+// of the Opensky API, so a shorter interval exhausts the daily credits.
+const DefaultBoardWorldwideInterval = 5 * time.Minute
+
+// boardMatchWindow is the maximum time between a movement detected in the live states and a flight from the
+// arrival or departure endpoint for them to be considered the same movement
+const boardMatchWindow = 30 * time.Minute
+
+// BoardDirection describes whether a BoardEntry is an arrival or departure
+type BoardDirection int
+
+const (
+	// Arrival is a flight to the airport of the board
+	Arrival BoardDirection = iota
+	// Departure is a flight from the airport of the board
+	Departure
+)
+
+// String returns the name of the direction
+func (d BoardDirection) String() string {
+	switch d {
+	case Arrival:
+		return "arrival"
+	case Departure:
+		return "departure"
+	}
+
+	return fmt.Sprintf("BoardDirection(%d)", int(d))
+}
+
+// BoardStatus is the status of a flight on an AirportBoard
+type BoardStatus int
+
+const (
+	// Approaching is an arriving aircraft flying towards the airport
+	Approaching BoardStatus = iota
+	// Landed is an aircraft which landed at the airport
+	Landed
+	// Airborne is a departed aircraft still flying near the airport
+	Airborne
+	// Departed is an aircraft which took off from the airport and left its vicinity
+	Departed
+)
+
+// String returns the name of the status
+func (s BoardStatus) String() string {
+	switch s {
+	case Approaching:
+		return "approaching"
+	case Landed:
+		return "landed"
+	case Airborne:
+		return "airborne"
+	case Departed:
+		return "departed"
+	}
+
+	return fmt.Sprintf("BoardStatus(%d)", int(s))
+}
+
+// BoardEntry is a flight on an AirportBoard
+type BoardEntry struct {
+	Direction BoardDirection
+	Status    BoardStatus
+	ICAO24    string
+	CallSign  string       // Callsign without padding, empty if unknown.
+	Time      time.Time    // Time of departure or landing, or the estimated time of arrival of an approaching aircraft.
+	Flight    *Flight      // Flight from the arrival or departure endpoint, nil if the flight is only seen live.
+	State     *StateVector // Latest live state near the airport, nil if the aircraft is not near the airport.
+	Distance  *float64     // Distance to the airport in meters, nil if the aircraft is not near the airport.
+}
+
+// BoardFlightsSource returns the arrivals and departures of an airport. The Flights service of a Client is a
+// BoardFlightsSource.
+type BoardFlightsSource interface {
+	GetArrivalsByAirport(airport string, begin, end time.Time) ([]Flight, error)
+	GetDeparturesByAirport(airport string, begin, end time.Time) ([]Flight, error)
+}
+
+// BoardStatesSource returns live state snapshots. The States service of a Client is a BoardStatesSource.
+type BoardStatesSource interface {
+	GetAllStates(time time.Time, icao24 string) (StatesResponse, error)
+	GetStatesInBoundingBox(time time.Time, box BoundingBox) (StatesResponse, error)
+}
+
+// AirportBoard combines the arrivals and departures of an airport with the live states near it, like the flight
+// information displays at an airport. The arrival and departure endpoints only contain flights once Opensky
+// has processed them, so takeoffs and landings are detected in the live states as well. Aircraft near the
+// airport which are neither approaching nor a known departure, like overflights, are not shown.
+//
+// The live states are requested in the bounding box around the circle of Radius around the airport. All states
+// are requested when the circle contains a pole or crosses the antimeridian.
+type AirportBoard struct {
+	Airport              Airport
+	Flights              BoardFlightsSource
+	States               BoardStatesSource
+	Window               time.Duration              // Period before now to show flights for, DefaultBoardWindow when zero. At most 7 days.
+	Radius               float64                    // Radius in meters around the airport live states are considered in.
+	MaxApproachDeviation float64                    // Maximum difference in degrees between the track and the bearing to the airport of an approaching aircraft.
+	FlightsInterval      time.Duration              // Minimum time between requests to the arrival and departure endpoints.
+	Interval             time.Duration              // Refresh interval of Run. When zero, DefaultBoardInterval or DefaultBoardWorldwideInterval when all states are requested.
+	OnUpdate             func(entries []BoardEntry) // Called by Run after every refresh, can be nil.
+	OnError              func(err error)            // Called by Run when a refresh fails, can be nil. The board keeps refreshing.
+	Now                  func() time.Time           // Returns the current time, time.Now when nil.
+
+	refresh     sync.Mutex
+	flights     []BoardEntry
+	flightsTime time.Time
+	detector    *MovementDetector
+	movements   []BoardEntry
+
+	mu      sync.RWMutex
+	entries []BoardEntry
+	updated time.Time
+}
+
+// NewAirportBoard creates an AirportBoard for the airport which retrieves its data with the client
+func NewAirportBoard(client *Client, airport Airport) *AirportBoard {
+	return &AirportBoard{
+		Airport:              airport,
+		Flights:              client.Flights,
+		States:               client.States,
+		Window:               DefaultBoardWindow,
+		Radius:               100000,
+		MaxApproachDeviation: 30,
+		FlightsInterval:      15 * time.Minute,
+	}
+}
+
+// Entries returns the entries of the last refresh, ordered by time
+func (b *AirportBoard) Entries() []BoardEntry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	entries := make([]BoardEntry, len(b.entries))
+	copy(entries, b.entries)
+
+	return entries
+}
+
+// Updated returns the time of the last successful refresh, the zero time if there has been none
+func (b *AirportBoard) Updated() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.updated
+}
+
+// Run refreshes the board every Interval until the context is done, it returns the error of the context
+func (b *AirportBoard) Run(ctx context.Context) error {
+	interval := b.Interval
+
+	if interval <= 0 {
+		interval = DefaultBoardWorldwideInterval
+
+		if _, ok := BoundingBoxAround(b.Airport.Position, b.Radius); ok {
+			interval = DefaultBoardInterval
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// The context is checked first, select picks randomly when the ticker fired as well
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if entries, err := b.Refresh(); err != nil {
+			if b.OnError != nil {
+				b.OnError(err)
+			}
+		} else if b.OnUpdate != nil {
+			b.OnUpdate(entries)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh retrieves the live states, and the arrivals and departures when they are older than FlightsInterval,
+// and returns the updated entries. The previous entries are kept when it fails.
+func (b *AirportBoard) Refresh() ([]BoardEntry, error) {
+	b.refresh.Lock()
+	defer b.refresh.Unlock()
+
+	now := time.Now()
+
+	if b.Now != nil {
+		now = b.Now()
+	}
+
+	window := b.Window
+
+	if window <= 0 {
+		window = DefaultBoardWindow
+	}
+
+	if b.flightsTime.IsZero() || now.Sub(b.flightsTime) >= b.FlightsInterval {
+		if err := b.refreshFlights(now.Add(-window), now); err != nil {
+			return nil, err
+		}
+
+		b.flightsTime = now
+	}
+
+	snapshot, err := b.states()
+
+	if err != nil {
+		return nil, err
+	}
+
+	b.detectMovements(snapshot, now.Add(-window))
+	entries := b.merge(snapshot)
+
+	b.mu.Lock()
+	b.entries = entries
+	b.updated = now
+	b.mu.Unlock()
+
+	return b.Entries(), nil
+}
+
+// states requests the live states around the airport, or all states when there is no bounding box around it
+func (b *AirportBoard) states() (StatesResponse, error) {
+	if box, ok := BoundingBoxAround(b.Airport.Position, b.Radius); ok {
+		return b.States.GetStatesInBoundingBox(time.Time{}, box)
+	}
+
+	return b.States.GetAllStates(time.Time{}, "")
+}
+
+func (b *AirportBoard) refreshFlights(begin, end time.Time) error {
+	arrivals, err := b.Flights.GetArrivalsByAirport(b.Airport.ICAO, begin, end)
+
+	if err != nil {
+		return err
+	}
+
+	departures, err := b.Flights.GetDeparturesByAirport(b.Airport.ICAO, begin, end)
+
+	if err != nil {
+		return err
+	}
+
+	flights := make([]BoardEntry, 0, len(arrivals)+len(departures))
+
+	for i := range arrivals {
+		f := arrivals[i]
+		flights = append(flights, BoardEntry{
+			Direction: Arrival,
+			Status:    Landed,
+			ICAO24:    f.ICAO24,
+			CallSign:  trimCallsign(f.CallSign),
+			Time:      time.Unix(f.LastSeen, 0),
+			Flight:    &f,
+		})
+	}
+
+	for i := range departures {
+		f := departures[i]
+		flights = append(flights, BoardEntry{
+			Direction: Departure,
+			Status:    Departed,
+			ICAO24:    f.ICAO24,
+			CallSign:  trimCallsign(f.CallSign),
+			Time:      time.Unix(f.FirstSeen, 0),
+			Flight:    &f,
+		})
+	}
+
+	b.flights = flights
+
+	return nil
+}
+
+// detectMovements adds the takeoffs and landings at the airport in the snapshot and forgets the ones before begin
+func (b *AirportBoard) detectMovements(snapshot StatesResponse, begin time.Time) {
+	if b.detector == nil {
+		b.detector = NewMovementDetector()
+	}
+
+	for _, m := range b.detector.Update(snapshot) {
+		if Distance(m.Position, b.Airport.Position) > b.detector.MaxAirportDistance {
+			continue
+		}
+
+		entry := BoardEntry{Direction: Departure, Status: Departed, ICAO24: m.ICAO24, CallSign: trimCallsign(m.CallSign), Time: m.Time}
+
+		if m.Type == Landing {
+			entry.Direction, entry.Status = Arrival, Landed
+		}
+
+		b.movements = append(b.movements, entry)
+	}
+
+	movements := b.movements[:0]
+
+	for _, m := range b.movements {
+		if !m.Time.Before(begin) {
+			movements = append(movements, m)
+		}
+	}
+
+	b.movements = movements
+}
+
+// merge combines the flights, detected movements and live states into the entries of the board
+func (b *AirportBoard) merge(snapshot StatesResponse) []BoardEntry {
+	entries := make([]BoardEntry, len(b.flights), len(b.flights)+len(b.movements))
+	copy(entries, b.flights)
+
+	for _, m := range b.movements {
+		if !containsMovement(entries, m) {
+			entries = append(entries, m)
+		}
+	}
+
+	// The latest departure of every aircraft, which is airborne if it is still near the airport
+	latest := make(map[string]int)
+
+	for i, e := range entries {
+		if j, ok := latest[e.ICAO24]; e.Direction == Departure && (!ok || e.Time.After(entries[j].Time)) {
+			latest[e.ICAO24] = i
+		}
+	}
+
+	for i := range snapshot.States {
+		s := snapshot.States[i]
+		position, ok := s.Position()
+
+		if !ok {
+			continue
+		}
+
+		distance := Distance(position, b.Airport.Position)
+
+		if distance > b.Radius {
+			continue
+		}
+
+		if j, ok := latest[s.ICAO24]; ok && !b.detector.onGround(s) {
+			entries[j].Status, entries[j].State, entries[j].Distance = Airborne, &s, &distance
+
+			continue
+		}
+
+		if eta, ok := b.approach(s, position, distance); ok {
+			entries = append(entries, BoardEntry{
+				Direction: Arrival,
+				Status:    Approaching,
+				ICAO24:    s.ICAO24,
+				CallSign:  trimCallsign(s.Callsign),
+				Time:      eta,
+				State:     &s,
+				Distance:  &distance,
+			})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Time.Equal(entries[j].Time) {
+			return entries[i].Time.Before(entries[j].Time)
+		}
+
+		return entries[i].ICAO24 < entries[j].ICAO24
+	})
+
+	return entries
+}
+
+// approach returns the estimated time of arrival of an airborne aircraft which flies towards the airport
+// without climbing
+func (b *AirportBoard) approach(s StateVector, position Point, distance float64) (time.Time, bool) {
+	if b.detector.onGround(s) || s.Velocity == nil || s.TrueTrack == nil || *s.Velocity <= 0 {
+		return time.Time{}, false
+	}
+
+	if s.VerticalRate != nil && *s.VerticalRate > 1 {
+		return time.Time{}, false
+	}
+
+	deviation := math.Abs(math.Remainder(*s.TrueTrack-InitialBearing(position, b.Airport.Position), 360))
+
+	if deviation > b.MaxApproachDeviation {
+		return time.Time{}, false
+	}
+
+	// The speed towards the airport, the remaining distance is assumed to be flown in a straight line
+	speed := *s.Velocity * math.Cos(radians(deviation))
+	eta := s.positionTime().Add(time.Duration(distance / speed * float64(time.Second)))
+
+	return eta, true
+}
+
+// containsMovement reports whether the entries contain the movement, a movement detected in the live states
+// matches a flight in the same direction of the aircraft within boardMatchWindow
+func containsMovement(entries []BoardEntry, m BoardEntry) bool {
+	for _, e := range entries {
+		if e.ICAO24 == m.ICAO24 && e.Direction == m.Direction {
+			if d := e.Time.Sub(m.Time); d >= -boardMatchWindow && d <= boardMatchWindow {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func trimCallsign(callsign *string) string {
+	if callsign == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(*callsign)
+}
@@ -0,0 +1,265 @@
+package goflight_test
+
+import (
+	"context"
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"testing"
+	"time"
+)
+
+type boardFlights struct {
+	arrivals   []goflight.Flight
+	departures []goflight.Flight
+	err        error
+	calls      int
+}
+
+func (f *boardFlights) GetArrivalsByAirport(airport string, begin, end time.Time) ([]goflight.Flight, error) {
+	f.calls++
+
+	return f.arrivals, f.err
+}
+
+func (f *boardFlights) GetDeparturesByAirport(airport string, begin, end time.Time) ([]goflight.Flight, error) {
+	return f.departures, f.err
+}
+
+type boardStates struct {
+	snapshots []goflight.StatesResponse
+	err       error
+	boxes     []goflight.BoundingBox
+	all       int
+}
+
+func (s *boardStates) GetAllStates(time time.Time, icao24 string) (goflight.StatesResponse, error) {
+	s.all++
+
+	return s.next()
+}
+
+func (s *boardStates) GetStatesInBoundingBox(time time.Time, box goflight.BoundingBox) (goflight.StatesResponse, error) {
+	s.boxes = append(s.boxes, box)
+
+	return s.next()
+}
+
+func (s *boardStates) next() (goflight.StatesResponse, error) {
+	if s.err != nil {
+		return goflight.StatesResponse{}, s.err
+	}
+
+	snapshot := s.snapshots[0]
+
+	if len(s.snapshots) > 1 {
+		s.snapshots = s.snapshots[1:]
+	}
+
+	return snapshot, nil
+}
+
+func boardState(icao24 string, t int64, lat, lon, altitude, velocity, track, verticalRate float64, onGround bool) goflight.StateVector {
+	s := movementState(icao24, t, lat, lon, altitude, velocity, track, onGround)
+	s.VerticalRate = floatPtr(verticalRate)
+
+	return s
+}
+
+func newTestBoard(flights *boardFlights, states *boardStates) (*goflight.AirportBoard, *time.Time) {
+	client, _ := goflight.NewClient("", "", nil)
+	airport := goflight.Airport{ICAO: "EHAM", Position: goflight.Point{Latitude: 52.31, Longitude: 4.76}}
+	board := goflight.NewAirportBoard(client, airport)
+	board.Flights, board.States = flights, states
+	now := time.Unix(2000, 0)
+	board.Now = func() time.Time { return now }
+
+	return board, &now
+}
+
+func TestAirportBoard_Refresh(t *testing.T) {
+	arrivalCallsign, departureCallsign := "KLM12   ", "KLM34   "
+	flights := &boardFlights{
+		arrivals:   []goflight.Flight{{ICAO24: "aaaaa1", CallSign: &arrivalCallsign, FirstSeen: 0, LastSeen: 1000}},
+		departures: []goflight.Flight{{ICAO24: "dddd01", CallSign: &departureCallsign, FirstSeen: 1500, LastSeen: 9000}},
+	}
+	states := &boardStates{snapshots: []goflight.StatesResponse{
+		{Time: 2000, States: []goflight.StateVector{
+			boardState("dddd01", 2000, 52.40, 4.76, 1500, 100, 0, 10, false),
+			boardState("a00001", 2000, 51.86, 4.76, 3000, 100, 0, -5, false),
+			boardState("b00001", 2000, 52.13, 5.50, 3000, 100, 90, 0, false),
+			boardState("c00001", 2000, 52.31, 4.76, 0, 0, 0, 0, true),
+			boardState("e00001", 2000, 52.30, 4.76, 100, 70, 0, -3, false),
+			boardState("f00001", 2000, 48.00, 4.76, 3000, 100, 0, -5, false),
+		}},
+		{Time: 2010, States: []goflight.StateVector{
+			boardState("c00001", 2010, 52.32, 4.76, 100, 80, 0, 8, false),
+			boardState("e00001", 2010, 52.31, 4.76, 0, 50, 0, 0, true),
+		}},
+	}}
+	board, now := newTestBoard(flights, states)
+
+	entries, err := board.Refresh()
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected := []struct {
+		icao24    string
+		direction goflight.BoardDirection
+		status    goflight.BoardStatus
+	}{
+		{"aaaaa1", goflight.Arrival, goflight.Landed},
+		{"dddd01", goflight.Departure, goflight.Airborne},
+		{"e00001", goflight.Arrival, goflight.Approaching},
+		{"a00001", goflight.Arrival, goflight.Approaching},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %v entries to equal %v: %+v", len(entries), len(expected), entries)
+	}
+
+	for i, e := range expected {
+		if entries[i].ICAO24 != e.icao24 || entries[i].Direction != e.direction || entries[i].Status != e.status {
+			t.Errorf("expected entry %+v to be the %v %v of %v", entries[i], e.status, e.direction, e.icao24)
+		}
+	}
+
+	if entries[0].CallSign != "KLM12" || entries[0].Flight == nil || entries[0].State != nil {
+		t.Errorf("unexpected arrival: %+v", entries[0])
+	}
+
+	if entries[1].State == nil || entries[1].Distance == nil || *entries[1].Distance > 11000 {
+		t.Errorf("expected the live state of the departure: %+v", entries[1])
+	}
+
+	// 50 km to go at 100 m/s
+	if eta := entries[3].Time.Unix(); eta < 2000+490 || eta > 2000+510 {
+		t.Errorf("expected eta %v to be about 500 seconds after the position time", eta)
+	}
+
+	*now = now.Add(10 * time.Second)
+
+	if entries, err = board.Refresh(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if flights.calls != 1 {
+		t.Errorf("expected the flights to be requested once, got %v", flights.calls)
+	}
+
+	expected = []struct {
+		icao24    string
+		direction goflight.BoardDirection
+		status    goflight.BoardStatus
+	}{
+		{"aaaaa1", goflight.Arrival, goflight.Landed},
+		{"dddd01", goflight.Departure, goflight.Departed},
+		{"c00001", goflight.Departure, goflight.Airborne},
+		{"e00001", goflight.Arrival, goflight.Landed},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %v entries to equal %v: %+v", len(entries), len(expected), entries)
+	}
+
+	for i, e := range expected {
+		if entries[i].ICAO24 != e.icao24 || entries[i].Direction != e.direction || entries[i].Status != e.status {
+			t.Errorf("expected entry %+v to be the %v %v of %v", entries[i], e.status, e.direction, e.icao24)
+		}
+	}
+
+	if updated := board.Updated(); !updated.Equal(*now) {
+		t.Errorf("expected %v to equal %v", updated, *now)
+	}
+
+	if len(board.Entries()) != len(expected) {
+		t.Errorf("expected the entries of the last refresh")
+	}
+
+	// Only the area around the airport is requested
+	if len(states.boxes) != 2 || states.all != 0 {
+		t.Fatalf("expected 2 bounding box requests, got %v and %v requests of all states", len(states.boxes), states.all)
+	}
+
+	if box := states.boxes[0]; box.LaMin < 51.41 || box.LaMin > 51.42 || box.LaMax < 53.20 || box.LaMax > 53.21 || box.LoMin < 3.28 || box.LoMin > 3.29 || box.LoMax < 6.23 || box.LoMax > 6.24 {
+		t.Errorf("unexpected bounding box: %+v", box)
+	}
+}
+
+func TestAirportBoard_Refresh_Worldwide(t *testing.T) {
+	states := &boardStates{snapshots: []goflight.StatesResponse{{Time: 2000}}}
+	board, _ := newTestBoard(&boardFlights{}, states)
+
+	// The circle around the airport crosses the antimeridian
+	board.Airport.Position = goflight.Point{Latitude: -16.9, Longitude: 179.5}
+
+	if _, err := board.Refresh(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(states.boxes) != 0 || states.all != 1 {
+		t.Errorf("expected all states to be requested, got %v bounding box requests", len(states.boxes))
+	}
+}
+
+func TestAirportBoard_Refresh_Error(t *testing.T) {
+	flights := &boardFlights{err: errors.New("flights")}
+	states := &boardStates{snapshots: []goflight.StatesResponse{{Time: 2000}}}
+	board, _ := newTestBoard(flights, states)
+
+	if _, err := board.Refresh(); err != flights.err {
+		t.Errorf("expected %v to equal %v", err, flights.err)
+	}
+
+	flights.err = nil
+	states.err = errors.New("states")
+
+	if _, err := board.Refresh(); err != states.err {
+		t.Errorf("expected %v to equal %v", err, states.err)
+	}
+
+	if !board.Updated().IsZero() || len(board.Entries()) != 0 {
+		t.Errorf("expected the board not to be updated")
+	}
+}
+
+func TestAirportBoard_Run(t *testing.T) {
+	flights := &boardFlights{departures: []goflight.Flight{{ICAO24: "dddd01", FirstSeen: 1500}}}
+	states := &boardStates{snapshots: []goflight.StatesResponse{{Time: 2000}}}
+	board, _ := newTestBoard(flights, states)
+	board.Interval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := 0
+	board.OnUpdate = func(entries []goflight.BoardEntry) {
+		if len(entries) != 1 || entries[0].Status != goflight.Departed {
+			t.Errorf("unexpected entries: %+v", entries)
+		}
+
+		if updates++; updates == 3 {
+			cancel()
+		}
+	}
+
+	if err := board.Run(ctx); err != context.Canceled {
+		t.Errorf("expected %v to equal %v", err, context.Canceled)
+	}
+
+	if updates != 3 {
+		t.Errorf("expected %v updates to equal 3", updates)
+	}
+}
+
+func TestBoardStatus_String(t *testing.T) {
+	if s := goflight.Approaching.String(); s != "approaching" {
+		t.Errorf("expected %q to equal %q", s, "approaching")
+	}
+
+	if s := goflight.Departure.String(); s != "departure" {
+		t.Errorf("expected %q to equal %q", s, "departure")
+	}
+
+	if s := goflight.BoardStatus(7).String(); s != "BoardStatus(7)" {
+		t.Errorf("expected %q to equal %q", s, "BoardStatus(7)")
+	}
+}
@@ -0,0 +1,225 @@
+package goflight
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CallsignType describes the kind of callsign parsed by ParseCallsign
+type CallsignType int
+
+const (
+	// OtherCallsign is a callsign that is not recognized as any of the other types
+	OtherCallsign CallsignType = iota
+	// AirlineCallsign is an ICAO airline designator followed by a flight number, e.g. KLM1234
+	AirlineCallsign
+	// RegistrationCallsign is the registration of the aircraft, e.g. PHEXB or N286WN
+	RegistrationCallsign
+	// MilitaryCallsign is the callsign of a military operator, e.g. RCH123 or REACH12
+	MilitaryCallsign
+)
+
+// String returns the name of the callsign type
+func (t CallsignType) String() string {
+	switch t {
+	case OtherCallsign:
+		return "other"
+	case AirlineCallsign:
+		return "airline"
+	case RegistrationCallsign:
+		return "registration"
+	case MilitaryCallsign:
+		return "military"
+	}
+
+	return fmt.Sprintf("CallsignType(%d)", int(t))
+}
+
+// Callsign is a callsign split into its parts
+type Callsign struct {
+	Raw          string // Callsign as received, including padding.
+	Callsign     string // Callsign without padding in upper case.
+	Type         CallsignType
+	Designator   string   // ICAO designator of the operator, empty unless the callsign starts with one.
+	FlightNumber string   // Flight number following the designator, empty unless the callsign starts with one.
+	Airline      *Airline // Operator of the designator, nil if it is not in the airline database.
+	Registration string   // Registration with the nationality prefix separated by a hyphen, e.g. PH-EXB. Empty unless the type is RegistrationCallsign.
+}
+
+// registrationPrefixes maps nationality prefixes with registrations of letters to the number of letters
+// following the prefix
+var registrationPrefixes = map[string]int{
+	"C": 4, "D": 4, "F": 4, "G": 4, "I": 4, "M": 4,
+	"4X": 3, "5B": 3, "9A": 3, "9H": 3, "9M": 3, "9V": 3, "A6": 3, "A7": 3, "AP": 3, "CC": 3, "CN": 3, "CS": 3,
+	"EC": 3, "EI": 3, "ER": 3, "ES": 3, "EW": 3, "HA": 3, "HB": 3, "HS": 3, "HZ": 3, "LN": 3, "LV": 3, "LX": 3,
+	"LY": 3, "LZ": 3, "OE": 3, "OH": 3, "OK": 3, "OM": 3, "OO": 3, "OY": 3, "PH": 3, "PK": 3, "PP": 3, "PR": 3,
+	"PS": 3, "PT": 3, "RP": 3, "S5": 3, "SE": 3, "SP": 3, "SU": 3, "SX": 3, "TC": 3, "TF": 3, "UR": 3, "VH": 3,
+	"VT": 3, "XA": 3, "XB": 3, "XC": 3, "YL": 3, "YR": 3, "ZK": 3, "ZS": 3,
+}
+
+// numericRegistrationPrefixes maps nationality prefixes with registrations starting with a digit to the number
+// of characters following the prefix
+var numericRegistrationPrefixes = map[string]int{"B": 5, "HL": 4, "JA": 4}
+
+// militaryCallsignPrefixes are tactical callsigns used by military aircraft, followed by digits
+var militaryCallsignPrefixes = []string{
+	"ASCOT", "BRK", "DUKE", "EVAC", "KING", "NATO", "NAVY", "REACH", "SAM", "SPAR", "TARTAN", "VENUS",
+}
+
+// ParseCallsign parses a callsign as transmitted by the transponder, the airline of the designator is looked up
+// in the default airline database. An empty callsign results in an OtherCallsign with an empty Callsign.
+func ParseCallsign(callsign string) Callsign {
+	return DefaultAirlines().ParseCallsign(callsign)
+}
+
+// ParseCallsign parses a callsign as transmitted by the transponder, the airline of the designator is looked up
+// in the database
+func (db *AirlineDatabase) ParseCallsign(callsign string) Callsign {
+	c := Callsign{Raw: callsign, Callsign: strings.ToUpper(strings.TrimSpace(callsign))}
+	s := c.Callsign
+
+	// Tactical callsigns are checked first, SAM and BRK would otherwise be parsed as airline designators
+	if isMilitaryCallsign(s) {
+		c.Type = MilitaryCallsign
+
+		return c
+	}
+
+	if isAirlineCallsign(s) {
+		c.Type = AirlineCallsign
+		c.Designator, c.FlightNumber = s[:3], s[3:]
+
+		if airline, ok := db.Lookup(c.Designator); ok && airline.ICAO == c.Designator {
+			c.Airline = &airline
+
+			if airline.Military {
+				c.Type = MilitaryCallsign
+			}
+		}
+
+		return c
+	}
+
+	if registration, ok := formatRegistration(s); ok {
+		c.Type = RegistrationCallsign
+		c.Registration = registration
+	}
+
+	return c
+}
+
+// IATAFlightNumber returns the flight number with the IATA code of the airline, e.g. KL1234 for KLM1234. ok is
+// false when the airline or its IATA code is unknown, or the flight number is not numeric.
+func (c Callsign) IATAFlightNumber() (flightNumber string, ok bool) {
+	if c.Airline == nil || c.Airline.IATA == "" || !isDigits(c.FlightNumber) {
+		return "", false
+	}
+
+	number := strings.TrimLeft(c.FlightNumber, "0")
+
+	if number == "" {
+		number = "0"
+	}
+
+	return c.Airline.IATA + number, true
+}
+
+// ParseCallsign parses the callsign of the state vector, ok is false if it has none
+func (s StateVector) ParseCallsign() (c Callsign, ok bool) {
+	return parseOptionalCallsign(s.Callsign)
+}
+
+// ParseCallsign parses the callsign of the flight, ok is false if it has none
+func (f Flight) ParseCallsign() (c Callsign, ok bool) {
+	return parseOptionalCallsign(f.CallSign)
+}
+
+func parseOptionalCallsign(callsign *string) (Callsign, bool) {
+	if callsign == nil || strings.TrimSpace(*callsign) == "" {
+		return Callsign{}, false
+	}
+
+	return ParseCallsign(*callsign), true
+}
+
+// isAirlineCallsign reports whether s is three letters followed by a flight number starting with a digit
+func isAirlineCallsign(s string) bool {
+	return len(s) >= 4 && len(s) <= 7 && isLetters(s[:3]) && isDigits(s[3:4]) && isAlphanumeric(s[4:])
+}
+
+func isMilitaryCallsign(s string) bool {
+	for _, prefix := range militaryCallsignPrefixes {
+		if digits := strings.TrimPrefix(s, prefix); digits != s && len(digits) > 0 && len(digits) <= 4 && isDigits(digits) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatRegistration returns s with a hyphen after the nationality prefix if it is a registration
+func formatRegistration(s string) (string, bool) {
+	if isUSRegistration(s) {
+		return s, true
+	}
+
+	for i := 1; i <= 2 && i < len(s); i++ {
+		prefix, suffix := s[:i], s[i:]
+
+		if n, ok := registrationPrefixes[prefix]; ok && len(suffix) == n && isLetters(suffix) {
+			return prefix + "-" + suffix, true
+		}
+
+		if n, ok := numericRegistrationPrefixes[prefix]; ok && len(suffix) == n && isDigits(suffix[:1]) && isAlphanumeric(suffix) {
+			return prefix + "-" + suffix, true
+		}
+	}
+
+	return "", false
+}
+
+// isUSRegistration reports whether s is an N-number: N, a digit other than zero, up to four more digits and
+// at most two letters at the end, with at most five characters after the N
+func isUSRegistration(s string) bool {
+	if len(s) < 2 || len(s) > 6 || s[0] != 'N' || s[1] < '1' || s[1] > '9' {
+		return false
+	}
+
+	i := 2
+
+	for i < len(s) && isDigits(s[i:i+1]) {
+		i++
+	}
+
+	return len(s)-i <= 2 && isLetters(s[i:])
+}
+
+func isLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isLetters(s[i:i+1]) && !isDigits(s[i:i+1]) {
+			return false
+		}
+	}
+
+	return true
+}
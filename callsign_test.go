@@ -0,0 +1,208 @@
+package goflight_test
+
+import (
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+var parseCallsignTests = []struct {
+	callsign     string
+	trimmed      string
+	callsignType goflight.CallsignType
+	designator   string
+	flightNumber string
+	airline      string
+	registration string
+}{
+	{"KLM31   ", "KLM31", goflight.AirlineCallsign, "KLM", "31", "KLM Royal Dutch Airlines", ""},
+	{"QTR8171 ", "QTR8171", goflight.AirlineCallsign, "QTR", "8171", "Qatar Airways", ""},
+	{"dlh4ab", "DLH4AB", goflight.AirlineCallsign, "DLH", "4AB", "Lufthansa", ""},
+	{"BAW0012 ", "BAW0012", goflight.AirlineCallsign, "BAW", "0012", "British Airways", ""},
+	{"ZXP25   ", "ZXP25", goflight.AirlineCallsign, "ZXP", "25", "", ""},
+	{"RCH123  ", "RCH123", goflight.MilitaryCallsign, "RCH", "123", "United States Air Force Air Mobility Command", ""},
+	{"REACH12 ", "REACH12", goflight.MilitaryCallsign, "", "", "", ""},
+	{"NATO01", "NATO01", goflight.MilitaryCallsign, "", "", "", ""},
+	{"SAM123  ", "SAM123", goflight.MilitaryCallsign, "", "", "", ""},
+	{"BRK12", "BRK12", goflight.MilitaryCallsign, "", "", "", ""},
+	{"SAM1A", "SAM1A", goflight.AirlineCallsign, "SAM", "1A", "", ""},
+	{"N286WN  ", "N286WN", goflight.RegistrationCallsign, "", "", "", "N286WN"},
+	{"N1", "N1", goflight.RegistrationCallsign, "", "", "", "N1"},
+	{"PHEXB   ", "PHEXB", goflight.RegistrationCallsign, "", "", "", "PH-EXB"},
+	{"GEUUA", "GEUUA", goflight.RegistrationCallsign, "", "", "", "G-EUUA"},
+	{"DAIBA", "DAIBA", goflight.RegistrationCallsign, "", "", "", "D-AIBA"},
+	{"JA801A", "JA801A", goflight.RegistrationCallsign, "", "", "", "JA-801A"},
+	{"B18601", "B18601", goflight.RegistrationCallsign, "", "", "", "B-18601"},
+	{"DUKE", "DUKE", goflight.OtherCallsign, "", "", "", ""},
+	{"N0123", "N0123", goflight.OtherCallsign, "", "", "", ""},
+	{"N12ABC", "N12ABC", goflight.OtherCallsign, "", "", "", ""},
+	{"V8      ", "V8", goflight.OtherCallsign, "", "", "", ""},
+	{"CG151   ", "CG151", goflight.OtherCallsign, "", "", "", ""},
+	{"KLM", "KLM", goflight.OtherCallsign, "", "", "", ""},
+	{"KLM12345", "KLM12345", goflight.OtherCallsign, "", "", "", ""},
+	{"        ", "", goflight.OtherCallsign, "", "", "", ""},
+}
+
+func TestParseCallsign(t *testing.T) {
+	for _, tt := range parseCallsignTests {
+		t.Run(tt.callsign, func(t *testing.T) {
+			c := goflight.ParseCallsign(tt.callsign)
+
+			if c.Raw != tt.callsign || c.Callsign != tt.trimmed || c.Type != tt.callsignType {
+				t.Errorf("unexpected callsign %+v, expected %q of type %v", c, tt.trimmed, tt.callsignType)
+			}
+
+			if c.Designator != tt.designator || c.FlightNumber != tt.flightNumber {
+				t.Errorf("expected designator %q and flight number %q to equal %q and %q", c.Designator, c.FlightNumber, tt.designator, tt.flightNumber)
+			}
+
+			if airline := c.Airline; (airline == nil) != (tt.airline == "") || airline != nil && airline.Name != tt.airline {
+				t.Errorf("expected airline %+v to be %q", airline, tt.airline)
+			}
+
+			if c.Registration != tt.registration {
+				t.Errorf("expected registration %q to equal %q", c.Registration, tt.registration)
+			}
+		})
+	}
+}
+
+var parseCallsignWithoutAirlinesTests = []struct {
+	callsign     string
+	callsignType goflight.CallsignType
+	registration string
+}{
+	{"PHEXB   ", goflight.RegistrationCallsign, "PH-EXB"},
+	{"N286WN  ", goflight.RegistrationCallsign, "N286WN"},
+	{"REACH12 ", goflight.MilitaryCallsign, ""},
+	{"NATO01", goflight.MilitaryCallsign, ""},
+	{"SAM123  ", goflight.MilitaryCallsign, ""},
+	{"        ", goflight.OtherCallsign, ""},
+}
+
+func TestAirlineDatabase_ParseCallsign_WithoutAirlines(t *testing.T) {
+	db, err := goflight.LoadAirlines(strings.NewReader("icao,iata,name,callsign,country,military\n"))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Registrations, tactical callsigns and padding don't depend on the airline database
+	for _, tt := range parseCallsignWithoutAirlinesTests {
+		t.Run(tt.callsign, func(t *testing.T) {
+			c := db.ParseCallsign(tt.callsign)
+
+			if c.Type != tt.callsignType || c.Registration != tt.registration || c.Callsign != strings.TrimSpace(tt.callsign) {
+				t.Errorf("unexpected callsign %+v, expected type %v and registration %q", c, tt.callsignType, tt.registration)
+			}
+		})
+	}
+}
+
+var iataFlightNumberTests = []struct {
+	callsign     string
+	flightNumber string
+	ok           bool
+}{
+	{"KLM31", "KL31", true},
+	{"BAW0012", "BA12", true},
+	{"SKW3609", "OO3609", true},
+	{"DLH4AB", "", false},
+	{"ZXP25", "", false},
+	{"RCH123", "", false},
+	{"PHEXB", "", false},
+}
+
+func TestCallsign_IATAFlightNumber(t *testing.T) {
+	for _, tt := range iataFlightNumberTests {
+		t.Run(tt.callsign, func(t *testing.T) {
+			flightNumber, ok := goflight.ParseCallsign(tt.callsign).IATAFlightNumber()
+
+			if flightNumber != tt.flightNumber || ok != tt.ok {
+				t.Errorf("expected (%q, %v) to equal (%q, %v)", flightNumber, ok, tt.flightNumber, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCallsignType_String(t *testing.T) {
+	if s := goflight.MilitaryCallsign.String(); s != "military" {
+		t.Errorf("expected %q to equal %q", s, "military")
+	}
+
+	if s := goflight.CallsignType(9).String(); s != "CallsignType(9)" {
+		t.Errorf("expected %q to equal %q", s, "CallsignType(9)")
+	}
+}
+
+func TestStateVector_ParseCallsign(t *testing.T) {
+	for _, s := range readMockStates(t) {
+		c, ok := s.ParseCallsign()
+
+		if ok != (s.Callsign != nil && strings.TrimSpace(*s.Callsign) != "") {
+			t.Errorf("unexpected ok %v for callsign %v", ok, s.Callsign)
+		}
+
+		if ok && c.Raw != *s.Callsign {
+			t.Errorf("expected %q to equal %q", c.Raw, *s.Callsign)
+		}
+	}
+
+	if _, ok := (goflight.Flight{}).ParseCallsign(); ok {
+		t.Errorf("expected no callsign for a flight without one")
+	}
+
+	callsign := "KLM31   "
+
+	if c, ok := (goflight.Flight{CallSign: &callsign}).ParseCallsign(); !ok || c.Designator != "KLM" {
+		t.Errorf("unexpected callsign: %+v", c)
+	}
+}
+
+func TestLoadAirlines(t *testing.T) {
+	data := `icao,iata,name,callsign,country,military
+KLM,KL,KLM Royal Dutch Airlines,KLM,NL,false
+XKL,KL,Duplicate IATA,TEST,XX,
+NAF,,Royal Netherlands Air and Space Force,NETHERLANDS AIR FORCE,NL,true
+`
+	db, err := goflight.LoadAirlines(strings.NewReader(data))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if airline, ok := db.Lookup("kl"); !ok || airline.ICAO != "KLM" {
+		t.Errorf("unexpected airline: %+v", airline)
+	}
+
+	if c := db.ParseCallsign("NAF01"); c.Type != goflight.MilitaryCallsign || c.Airline == nil || !c.Airline.Military {
+		t.Errorf("unexpected callsign: %+v", c)
+	}
+
+	// An IATA code is not an ICAO designator
+	if c := db.ParseCallsign("KL1234"); c.Airline != nil {
+		t.Errorf("unexpected airline: %+v", c.Airline)
+	}
+
+	if len(db.Airlines()) != 3 {
+		t.Errorf("expected %v airlines to equal 3", len(db.Airlines()))
+	}
+
+	if _, err = goflight.LoadAirlines(strings.NewReader(strings.Replace(data, ",true", ",maybe", 1))); err == nil {
+		t.Errorf("expected an error for an invalid military value")
+	}
+}
+
+// TestEmbeddedAirlines fails when data.go wasn't generated again after a change to data/airlines.csv
+func TestEmbeddedAirlines(t *testing.T) {
+	data, err := ioutil.ReadFile("./data/airlines.csv")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if string(data) != goflight.EmbeddedAirlines {
+		t.Error("expected the generated airline dataset to equal data/airlines.csv, run go generate")
+	}
+}
@@ -0,0 +1,565 @@
+package goflight
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type stateVectorColumn struct {
+	name   string
+	format func(s *StateVector) string
+	parse  func(s *StateVector, value string) error
+}
+
+type flightColumn struct {
+	name   string
+	format func(f *Flight) string
+	parse  func(f *Flight, value string) error
+}
+
+// stateVectorColumns are the CSV columns of a StateVector, named and ordered like the Opensky API documentation
+var stateVectorColumns = []stateVectorColumn{
+	{"icao24", func(s *StateVector) string { return s.ICAO24 }, func(s *StateVector, v string) error { s.ICAO24 = v; return nil }},
+	{"callsign", func(s *StateVector) string { return formatCSVString(s.Callsign) }, func(s *StateVector, v string) error { s.Callsign = parseCSVString(v); return nil }},
+	{"origin_country", func(s *StateVector) string { return s.OriginCountry }, func(s *StateVector, v string) error { s.OriginCountry = v; return nil }},
+	{"time_position", func(s *StateVector) string { return formatCSVInt64(s.TimePosition) }, func(s *StateVector, v string) (err error) { s.TimePosition, err = parseCSVInt64(v); return }},
+	{"last_contact", func(s *StateVector) string { return strconv.FormatInt(s.LastContact, 10) }, func(s *StateVector, v string) (err error) { s.LastContact, err = strconv.ParseInt(v, 10, 64); return }},
+	{"longitude", func(s *StateVector) string { return formatCSVFloat(s.Longitude) }, func(s *StateVector, v string) (err error) { s.Longitude, err = parseCSVFloat(v); return }},
+	{"latitude", func(s *StateVector) string { return formatCSVFloat(s.Latitude) }, func(s *StateVector, v string) (err error) { s.Latitude, err = parseCSVFloat(v); return }},
+	{"baro_altitude", func(s *StateVector) string { return formatCSVFloat(s.BaroAltitude) }, func(s *StateVector, v string) (err error) { s.BaroAltitude, err = parseCSVFloat(v); return }},
+	{"on_ground", func(s *StateVector) string { return strconv.FormatBool(s.OnGround) }, func(s *StateVector, v string) (err error) { s.OnGround, err = strconv.ParseBool(v); return }},
+	{"velocity", func(s *StateVector) string { return formatCSVFloat(s.Velocity) }, func(s *StateVector, v string) (err error) { s.Velocity, err = parseCSVFloat(v); return }},
+	{"true_track", func(s *StateVector) string { return formatCSVFloat(s.TrueTrack) }, func(s *StateVector, v string) (err error) { s.TrueTrack, err = parseCSVFloat(v); return }},
+	{"vertical_rate", func(s *StateVector) string { return formatCSVFloat(s.VerticalRate) }, func(s *StateVector, v string) (err error) { s.VerticalRate, err = parseCSVFloat(v); return }},
+	{"sensors", func(s *StateVector) string { return formatCSVInts(s.Sensors) }, func(s *StateVector, v string) (err error) { s.Sensors, err = parseCSVInts(v); return }},
+	{"geo_altitude", func(s *StateVector) string { return formatCSVFloat(s.GeoAltitude) }, func(s *StateVector, v string) (err error) { s.GeoAltitude, err = parseCSVFloat(v); return }},
+	{"squawk", func(s *StateVector) string { return formatCSVString(s.Squawk) }, func(s *StateVector, v string) error { s.Squawk = parseCSVString(v); return nil }},
+	{"spi", func(s *StateVector) string { return formatCSVBool(s.Spi) }, func(s *StateVector, v string) (err error) { s.Spi, err = parseCSVBool(v); return }},
+	{"position_source", func(s *StateVector) string { return strconv.Itoa(s.PositionSource) }, func(s *StateVector, v string) (err error) { s.PositionSource, err = strconv.Atoi(v); return }},
+	{"category", func(s *StateVector) string { return formatCSVInt(s.Category) }, func(s *StateVector, v string) (err error) { s.Category, err = parseCSVInt(v); return }},
+}
+
+// flightColumns are the CSV columns of a Flight, named and ordered like the Opensky API documentation
+var flightColumns = []flightColumn{
+	{"icao24", func(f *Flight) string { return f.ICAO24 }, func(f *Flight, v string) error { f.ICAO24 = v; return nil }},
+	{"firstSeen", func(f *Flight) string { return strconv.FormatInt(f.FirstSeen, 10) }, func(f *Flight, v string) (err error) { f.FirstSeen, err = strconv.ParseInt(v, 10, 64); return }},
+	{"estDepartureAirport", func(f *Flight) string { return formatCSVString(f.EstDepartureAirport) }, func(f *Flight, v string) error { f.EstDepartureAirport = parseCSVString(v); return nil }},
+	{"lastSeen", func(f *Flight) string { return strconv.FormatInt(f.LastSeen, 10) }, func(f *Flight, v string) (err error) { f.LastSeen, err = strconv.ParseInt(v, 10, 64); return }},
+	{"estArrivalAirport", func(f *Flight) string { return formatCSVString(f.EstArrivalAirport) }, func(f *Flight, v string) error { f.EstArrivalAirport = parseCSVString(v); return nil }},
+	{"callsign", func(f *Flight) string { return formatCSVString(f.CallSign) }, func(f *Flight, v string) error { f.CallSign = parseCSVString(v); return nil }},
+	{"estDepartureAirportHorizDistance", func(f *Flight) string { return formatCSVInt64(f.EstDepartureAirportHorizontalDistance) }, func(f *Flight, v string) (err error) { f.EstDepartureAirportHorizontalDistance, err = parseCSVInt64(v); return }},
+	{"estDepartureAirportVertDistance", func(f *Flight) string { return formatCSVInt64(f.EstDepartureAirportVerticalDistance) }, func(f *Flight, v string) (err error) { f.EstDepartureAirportVerticalDistance, err = parseCSVInt64(v); return }},
+	{"estArrivalAirportHorizDistance", func(f *Flight) string { return formatCSVInt64(f.EstArrivalAirportHorizontalDistance) }, func(f *Flight, v string) (err error) { f.EstArrivalAirportHorizontalDistance, err = parseCSVInt64(v); return }},
+	{"estArrivalAirportVertDistance", func(f *Flight) string { return formatCSVInt64(f.EstArrivalAirportVerticalDistance) }, func(f *Flight, v string) (err error) { f.EstArrivalAirportVerticalDistance, err = parseCSVInt64(v); return }},
+	{"departureAirportCandidatesCount", func(f *Flight) string { return strconv.FormatInt(f.DepartureAirportCandidatesCount, 10) }, func(f *Flight, v string) (err error) { f.DepartureAirportCandidatesCount, err = strconv.ParseInt(v, 10, 64); return }},
+	{"arrivalAirportCandidatesCount", func(f *Flight) string { return strconv.FormatInt(f.ArrivalAirportCandidatesCount, 10) }, func(f *Flight, v string) (err error) { f.ArrivalAirportCandidatesCount, err = strconv.ParseInt(v, 10, 64); return }},
+}
+
+// StateVectorWriter writes state vectors as CSV records, preceded by a header record. Nil values are written as
+// empty cells and empty cells are read as nil, so an empty callsign, squawk or sensors list is read back as nil.
+type StateVectorWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+// NewStateVectorWriter creates a StateVectorWriter. Use ',' as separator for CSV and '\t' for TSV.
+func NewStateVectorWriter(w io.Writer, comma rune) *StateVectorWriter {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	return &StateVectorWriter{w: writer}
+}
+
+// Write writes a single state vector. Nil values are written as empty cells.
+func (w *StateVectorWriter) Write(s StateVector) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(stateVectorColumns))
+
+	for i, column := range stateVectorColumns {
+		record[i] = column.format(&s)
+	}
+
+	return w.w.Write(record)
+}
+
+// writeHeader writes the header record if it has not been written yet
+func (w *StateVectorWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+
+	header := make([]string, len(stateVectorColumns))
+
+	for i, column := range stateVectorColumns {
+		header[i] = column.name
+	}
+
+	w.headerWritten = true
+
+	return w.w.Write(header)
+}
+
+// WriteAll writes the state vectors and flushes the writer. The header is written even if there are no state vectors.
+func (w *StateVectorWriter) WriteAll(states []StateVector) error {
+	for _, s := range states {
+		if err := w.Write(s); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// Flush writes any buffered data to the underlying writer, preceded by the header if no state vector has been written
+func (w *StateVectorWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	w.w.Flush()
+
+	return w.w.Error()
+}
+
+// StateVectorReader reads state vectors from CSV records. The first record must be a header,
+// columns are matched by name so their order does not matter and unknown columns are ignored.
+// Read returns a CSVParseError with ErrMissingColumn when a required column is missing from the header.
+type StateVectorReader struct {
+	r       *csv.Reader
+	line    int
+	columns []int
+	err     error
+}
+
+// NewStateVectorReader creates a StateVectorReader. Use ',' as separator for CSV and '\t' for TSV.
+func NewStateVectorReader(r io.Reader, comma rune) *StateVectorReader {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	return &StateVectorReader{r: reader}
+}
+
+// Read reads the next state vector. It returns io.EOF when there are no more records.
+func (r *StateVectorReader) Read() (StateVector, error) {
+	var s StateVector
+
+	if r.err != nil {
+		return s, r.err
+	}
+
+	if r.columns == nil {
+		header, err := r.r.Read()
+
+		if err != nil {
+			return s, err
+		}
+
+		r.line++
+		columns := csvColumnIndexes(header, len(stateVectorColumns), func(i int) string { return stateVectorColumns[i].name })
+
+		if r.err = requireCSVColumns(columns, func(i int) string { return stateVectorColumns[i].name }, "icao24"); r.err != nil {
+			return s, r.err
+		}
+
+		r.columns = columns
+	}
+
+	record, err := r.r.Read()
+
+	if err != nil {
+		return s, err
+	}
+
+	r.line++
+
+	for i, column := range stateVectorColumns {
+		index := r.columns[i]
+
+		if index < 0 || index >= len(record) {
+			continue
+		}
+
+		if err = column.parse(&s, record[index]); err != nil {
+			return s, &CSVParseError{Line: r.line, Column: column.name, Err: err}
+		}
+	}
+
+	return s, nil
+}
+
+// ReadAll reads all remaining state vectors
+func (r *StateVectorReader) ReadAll() ([]StateVector, error) {
+	var states []StateVector
+
+	for {
+		s, err := r.Read()
+
+		if err == io.EOF {
+			return states, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		states = append(states, s)
+	}
+}
+
+// FlightWriter writes flights as CSV records, preceded by a header record. Nil values are written as empty cells
+// and empty cells are read as nil, so an empty airport or callsign is read back as nil.
+type FlightWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+// NewFlightWriter creates a FlightWriter. Use ',' as separator for CSV and '\t' for TSV.
+func NewFlightWriter(w io.Writer, comma rune) *FlightWriter {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+
+	return &FlightWriter{w: writer}
+}
+
+// Write writes a single flight. Nil values are written as empty cells.
+func (w *FlightWriter) Write(f Flight) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(flightColumns))
+
+	for i, column := range flightColumns {
+		record[i] = column.format(&f)
+	}
+
+	return w.w.Write(record)
+}
+
+// writeHeader writes the header record if it has not been written yet
+func (w *FlightWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+
+	header := make([]string, len(flightColumns))
+
+	for i, column := range flightColumns {
+		header[i] = column.name
+	}
+
+	w.headerWritten = true
+
+	return w.w.Write(header)
+}
+
+// WriteAll writes the flights and flushes the writer. The header is written even if there are no flights.
+func (w *FlightWriter) WriteAll(flights []Flight) error {
+	for _, f := range flights {
+		if err := w.Write(f); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// Flush writes any buffered data to the underlying writer, preceded by the header if no flight has been written
+func (w *FlightWriter) Flush() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	w.w.Flush()
+
+	return w.w.Error()
+}
+
+// FlightReader reads flights from CSV records. The first record must be a header,
+// columns are matched by name so their order does not matter and unknown columns are ignored.
+// Read returns a CSVParseError with ErrMissingColumn when a required column is missing from the header.
+type FlightReader struct {
+	r       *csv.Reader
+	line    int
+	columns []int
+	err     error
+}
+
+// NewFlightReader creates a FlightReader. Use ',' as separator for CSV and '\t' for TSV.
+func NewFlightReader(r io.Reader, comma rune) *FlightReader {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	return &FlightReader{r: reader}
+}
+
+// Read reads the next flight. It returns io.EOF when there are no more records.
+func (r *FlightReader) Read() (Flight, error) {
+	var f Flight
+
+	if r.err != nil {
+		return f, r.err
+	}
+
+	if r.columns == nil {
+		header, err := r.r.Read()
+
+		if err != nil {
+			return f, err
+		}
+
+		r.line++
+		columns := csvColumnIndexes(header, len(flightColumns), func(i int) string { return flightColumns[i].name })
+
+		if r.err = requireCSVColumns(columns, func(i int) string { return flightColumns[i].name }, "icao24", "firstSeen", "lastSeen"); r.err != nil {
+			return f, r.err
+		}
+
+		r.columns = columns
+	}
+
+	record, err := r.r.Read()
+
+	if err != nil {
+		return f, err
+	}
+
+	r.line++
+
+	for i, column := range flightColumns {
+		index := r.columns[i]
+
+		if index < 0 || index >= len(record) {
+			continue
+		}
+
+		if err = column.parse(&f, record[index]); err != nil {
+			return f, &CSVParseError{Line: r.line, Column: column.name, Err: err}
+		}
+	}
+
+	return f, nil
+}
+
+// ReadAll reads all remaining flights
+func (r *FlightReader) ReadAll() ([]Flight, error) {
+	var flights []Flight
+
+	for {
+		f, err := r.Read()
+
+		if err == io.EOF {
+			return flights, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		flights = append(flights, f)
+	}
+}
+
+// CSVParseError is returned when a CSV cell can't be parsed
+type CSVParseError struct {
+	Line   int
+	Column string
+	Err    error
+}
+
+func (e *CSVParseError) Error() string {
+	return fmt.Sprintf("line %d, column %q: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns the underlying parse error
+func (e *CSVParseError) Unwrap() error {
+	return e.Err
+}
+
+// csvColumnIndexes maps every known column to its index in the header, or -1 if it is missing
+func csvColumnIndexes(header []string, count int, name func(i int) string) []int {
+	positions := make(map[string]int, len(header))
+
+	for i, h := range header {
+		positions[strings.TrimSpace(h)] = i
+	}
+
+	indexes := make([]int, count)
+
+	for i := range indexes {
+		index, ok := positions[name(i)]
+
+		if !ok {
+			index = -1
+		}
+
+		indexes[i] = index
+	}
+
+	return indexes
+}
+
+// requireCSVColumns returns a CSVParseError for the first required column which is missing from the header, so
+// a file without header or with another separator isn't read as empty records
+func requireCSVColumns(indexes []int, name func(i int) string, required ...string) error {
+	for _, column := range required {
+		for i, index := range indexes {
+			if name(i) == column && index < 0 {
+				return &CSVParseError{Line: 1, Column: column, Err: ErrMissingColumn}
+			}
+		}
+	}
+
+	return nil
+}
+
+func formatCSVString(v *string) string {
+	if v == nil {
+		return ""
+	}
+
+	return *v
+}
+
+// parseCSVString returns nil for an empty cell, an empty string can't be told apart from nil
+func parseCSVString(v string) *string {
+	if v == "" {
+		return nil
+	}
+
+	return &v
+}
+
+func formatCSVInt64(v *int64) string {
+	if v == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(*v, 10)
+}
+
+func parseCSVInt64(v string) (*int64, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	i, err := strconv.ParseInt(v, 10, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+func formatCSVInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*v)
+}
+
+func parseCSVInt(v string) (*int, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	i, err := strconv.Atoi(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
+
+func formatCSVFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
+
+func parseCSVFloat(v string) (*float64, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+func formatCSVBool(v *bool) string {
+	if v == nil {
+		return ""
+	}
+
+	return strconv.FormatBool(*v)
+}
+
+func parseCSVBool(v string) (*bool, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &b, nil
+}
+
+// formatCSVInts writes the values separated by semicolons
+func formatCSVInts(v *[]int) string {
+	if v == nil {
+		return ""
+	}
+
+	values := make([]string, len(*v))
+
+	for i, value := range *v {
+		values[i] = strconv.Itoa(value)
+	}
+
+	return strings.Join(values, ";")
+}
+
+func parseCSVInts(v string) (*[]int, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(v, ";")
+	values := make([]int, len(parts))
+
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+	}
+
+	return &values, nil
+}
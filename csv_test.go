@@ -0,0 +1,218 @@
+package goflight_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readMockStates(t *testing.T) []goflight.StateVector {
+	data, err := ioutil.ReadFile("./mocks/states.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var response goflight.StatesResponse
+
+	if err = json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	data, err = ioutil.ReadFile("./mocks/state_vector_category.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var vector goflight.StateVector
+
+	if err = json.Unmarshal(data, &vector); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return append(response.States, vector)
+}
+
+func TestStateVectorWriter_RoundTrip(t *testing.T) {
+	states := readMockStates(t)
+
+	for _, comma := range []rune{',', '\t'} {
+		var buf bytes.Buffer
+
+		if err := goflight.NewStateVectorWriter(&buf, comma).WriteAll(states); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		header := strings.SplitN(buf.String(), "\n", 2)[0]
+		expectedHeader := strings.Replace("icao24,callsign,origin_country,time_position,last_contact,longitude,latitude,baro_altitude,on_ground,velocity,true_track,vertical_rate,sensors,geo_altitude,squawk,spi,position_source,category", ",", string(comma), -1)
+
+		if header != expectedHeader {
+			t.Errorf("expected %v to equal %v", header, expectedHeader)
+		}
+
+		result, err := goflight.NewStateVectorReader(&buf, comma).ReadAll()
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if !reflect.DeepEqual(result, states) {
+			t.Errorf("expected %+v to equal %+v", result, states)
+		}
+	}
+}
+
+func TestStateVectorReader_Read(t *testing.T) {
+	input := "latitude,icao24,unknown,last_contact\n52.3,484ac1,x,1586031309\n,4846e1,y,1586031292\n"
+	states, err := goflight.NewStateVectorReader(strings.NewReader(input), ',').ReadAll()
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(states) != 2 || states[0].ICAO24 != "484ac1" || *states[0].Latitude != 52.3 || states[1].Latitude != nil {
+		t.Errorf("unexpected states: %+v", states)
+	}
+
+	_, err = goflight.NewStateVectorReader(strings.NewReader("icao24,velocity\n484ac1,fast\n"), ',').ReadAll()
+
+	var parseErr *goflight.CSVParseError
+
+	if !errors.As(err, &parseErr) || parseErr.Line != 2 || parseErr.Column != "velocity" {
+		t.Errorf("expected a parse error for line 2 column velocity, got %v", err)
+	}
+}
+
+func TestFlightWriter_RoundTrip(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/flights.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var flights []goflight.Flight
+
+	if err = json.Unmarshal(data, &flights); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var buf bytes.Buffer
+
+	if err = goflight.NewFlightWriter(&buf, ',').WriteAll(flights); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !strings.HasPrefix(buf.String(), "icao24,firstSeen,estDepartureAirport,lastSeen,") {
+		t.Errorf("unexpected header: %v", strings.SplitN(buf.String(), "\n", 2)[0])
+	}
+
+	result, err := goflight.NewFlightReader(&buf, ',').ReadAll()
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !reflect.DeepEqual(result, flights) {
+		t.Errorf("expected %+v to equal %+v", result, flights)
+	}
+}
+
+func TestCSVWriter_Empty(t *testing.T) {
+	var states, flights bytes.Buffer
+
+	if err := goflight.NewStateVectorWriter(&states, ',').WriteAll(nil); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := goflight.NewFlightWriter(&flights, ',').WriteAll([]goflight.Flight{}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if lines := strings.Split(states.String(), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], "icao24,callsign,") {
+		t.Errorf("expected only a header, got %q", states.String())
+	}
+
+	if lines := strings.Split(flights.String(), "\n"); len(lines) != 2 || !strings.HasPrefix(lines[0], "icao24,firstSeen,") {
+		t.Errorf("expected only a header, got %q", flights.String())
+	}
+
+	if result, err := goflight.NewStateVectorReader(&states, ',').ReadAll(); err != nil || len(result) != 0 {
+		t.Errorf("unexpected states %v and error %v", result, err)
+	}
+
+	// The header is written once when flushing after a write
+	w := goflight.NewFlightWriter(&flights, ',')
+	flights.Reset()
+
+	if err := w.Write(goflight.Flight{ICAO24: "484ac1"}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := w.Flush(); err != nil || strings.Count(flights.String(), "icao24") != 1 {
+		t.Errorf("unexpected output %q and error %v", flights.String(), err)
+	}
+}
+
+func TestStateVectorWriter_EmptyValues(t *testing.T) {
+	// Empty values are written as empty cells like nil values, so they are read back as nil
+	callsign, squawk, sensors := "", "", []int{}
+	state := goflight.StateVector{ICAO24: "484ac1", Callsign: &callsign, Squawk: &squawk, Sensors: &sensors}
+	var buf bytes.Buffer
+
+	if err := goflight.NewStateVectorWriter(&buf, ',').WriteAll([]goflight.StateVector{state}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	result, err := goflight.NewStateVectorReader(&buf, ',').ReadAll()
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(result) != 1 || result[0].Callsign != nil || result[0].Squawk != nil || result[0].Sensors != nil {
+		t.Errorf("expected empty values to be read as nil: %+v", result)
+	}
+}
+
+var missingCSVColumnTests = []struct {
+	label  string
+	data   string
+	flight bool
+	column string
+}{
+	{"headerless states", "484ac1,ZXP25,Kingdom of the Netherlands\n4846e1,V8,Kingdom of the Netherlands\n", false, "icao24"},
+	{"states with another separator", "icao24;callsign\n484ac1;ZXP25\n", false, "icao24"},
+	{"headerless flights", "484ac1,1517227688,1517230737\n", true, "icao24"},
+	{"flights without lastSeen", "icao24,firstSeen\n484ac1,1517227688\n", true, "lastSeen"},
+}
+
+func TestCSVReader_MissingColumn(t *testing.T) {
+	for _, tt := range missingCSVColumnTests {
+		t.Run(tt.label, func(t *testing.T) {
+			var read func() error
+
+			if tt.flight {
+				r := goflight.NewFlightReader(strings.NewReader(tt.data), ',')
+				read = func() error { _, err := r.Read(); return err }
+			} else {
+				r := goflight.NewStateVectorReader(strings.NewReader(tt.data), ',')
+				read = func() error { _, err := r.Read(); return err }
+			}
+
+			// The error is returned again, instead of reading the next record as header
+			for i := 0; i < 2; i++ {
+				var parseErr *goflight.CSVParseError
+
+				if err := read(); !errors.As(err, &parseErr) || parseErr.Line != 1 || parseErr.Column != tt.column || !errors.Is(err, goflight.ErrMissingColumn) {
+					t.Errorf("expected a missing %v column error, got %v", tt.column, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,387 @@
+package goflight
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// stateVectorValues holds the values of the pointer fields of a StateVector decoded by the fast decoder,
+// so a single allocation is needed for all of them
+type stateVectorValues struct {
+	callsign     string
+	timePosition int64
+	longitude    float64
+	latitude     float64
+	baroAltitude float64
+	velocity     float64
+	trueTrack    float64
+	verticalRate float64
+	geoAltitude  float64
+	squawk       string
+	spi          bool
+	category     int
+}
+
+// stateVectorScanner is a reflection free decoder for the positional array of a state vector. It only accepts
+// the common form of the array: strings without escape sequences, integers for the integer fields and no null
+// values for the non pointer fields. Any other input is rejected, so it can be decoded by encoding/json instead.
+type stateVectorScanner struct {
+	buf []byte
+	pos int
+}
+
+// decodeStateVector decodes buf onto s and returns true, or returns false without modifying s when buf
+// is not in the form accepted by the stateVectorScanner
+func decodeStateVector(buf []byte, s *StateVector) bool {
+	d := stateVectorScanner{buf: buf}
+	values := &stateVectorValues{}
+	var result StateVector
+	var ok, null bool
+
+	if !d.consume('[') {
+		return false
+	}
+
+	if result.ICAO24, ok = d.string(); !ok || !d.comma() {
+		return false
+	}
+
+	if null, ok = d.optionalString(&values.callsign); !ok || !d.comma() {
+		return false
+	} else if !null {
+		result.Callsign = &values.callsign
+	}
+
+	if result.OriginCountry, ok = d.string(); !ok || !d.comma() {
+		return false
+	}
+
+	if null, ok = d.optionalInt64(&values.timePosition); !ok || !d.comma() {
+		return false
+	} else if !null {
+		result.TimePosition = &values.timePosition
+	}
+
+	if result.LastContact, ok = d.int64(); !ok || !d.comma() {
+		return false
+	}
+
+	if !d.floatField(&values.longitude, &result.Longitude) ||
+		!d.floatField(&values.latitude, &result.Latitude) ||
+		!d.floatField(&values.baroAltitude, &result.BaroAltitude) {
+		return false
+	}
+
+	if result.OnGround, ok = d.bool(); !ok || !d.comma() {
+		return false
+	}
+
+	if !d.floatField(&values.velocity, &result.Velocity) ||
+		!d.floatField(&values.trueTrack, &result.TrueTrack) ||
+		!d.floatField(&values.verticalRate, &result.VerticalRate) {
+		return false
+	}
+
+	if result.Sensors, ok = d.sensors(); !ok || !d.comma() {
+		return false
+	}
+
+	if !d.floatField(&values.geoAltitude, &result.GeoAltitude) {
+		return false
+	}
+
+	if null, ok = d.optionalString(&values.squawk); !ok || !d.comma() {
+		return false
+	} else if !null {
+		result.Squawk = &values.squawk
+	}
+
+	if null, ok = d.optionalBool(&values.spi); !ok || !d.comma() {
+		return false
+	} else if !null {
+		result.Spi = &values.spi
+	}
+
+	var positionSource int64
+
+	if positionSource, ok = d.int64(); !ok {
+		return false
+	}
+
+	result.PositionSource = int(positionSource)
+
+	if d.consume(',') {
+		var category int64
+
+		if null, ok = d.optionalInt64(&category); !ok {
+			return false
+		} else if !null {
+			values.category = int(category)
+			result.Category = &values.category
+		}
+	}
+
+	if !d.consume(']') {
+		return false
+	}
+
+	d.skipWhitespace()
+
+	if d.pos != len(d.buf) {
+		return false
+	}
+
+	*s = result
+
+	return true
+}
+
+func (d *stateVectorScanner) skipWhitespace() {
+	for d.pos < len(d.buf) {
+		switch d.buf[d.pos] {
+		case ' ', '\t', '\n', '\r':
+			d.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (d *stateVectorScanner) consume(c byte) bool {
+	d.skipWhitespace()
+
+	if d.pos < len(d.buf) && d.buf[d.pos] == c {
+		d.pos++
+		return true
+	}
+
+	return false
+}
+
+func (d *stateVectorScanner) comma() bool {
+	return d.consume(',')
+}
+
+func (d *stateVectorScanner) literal(l string) bool {
+	d.skipWhitespace()
+
+	if len(d.buf)-d.pos >= len(l) && string(d.buf[d.pos:d.pos+len(l)]) == l {
+		d.pos += len(l)
+		return true
+	}
+
+	return false
+}
+
+func (d *stateVectorScanner) null() bool {
+	return d.literal("null")
+}
+
+func (d *stateVectorScanner) string() (string, bool) {
+	if !d.consume('"') {
+		return "", false
+	}
+
+	start := d.pos
+
+	for d.pos < len(d.buf) {
+		c := d.buf[d.pos]
+
+		switch {
+		case c == '"':
+			raw := d.buf[start:d.pos]
+			d.pos++
+
+			if !utf8.Valid(raw) {
+				return "", false
+			}
+
+			return string(raw), true
+		case c == '\\' || c < 0x20:
+			return "", false
+		}
+
+		d.pos++
+	}
+
+	return "", false
+}
+
+func (d *stateVectorScanner) optionalString(v *string) (null bool, ok bool) {
+	if d.null() {
+		return true, true
+	}
+
+	*v, ok = d.string()
+
+	return false, ok
+}
+
+// number returns the bytes of a JSON number, integer is true when it has no fraction or exponent
+func (d *stateVectorScanner) number() (raw []byte, integer bool, ok bool) {
+	d.skipWhitespace()
+	start := d.pos
+	integer = true
+
+	if d.pos < len(d.buf) && d.buf[d.pos] == '-' {
+		d.pos++
+	}
+
+	switch {
+	case d.pos < len(d.buf) && d.buf[d.pos] == '0':
+		d.pos++
+	case d.pos < len(d.buf) && d.buf[d.pos] >= '1' && d.buf[d.pos] <= '9':
+		d.digits()
+	default:
+		return nil, false, false
+	}
+
+	if d.pos < len(d.buf) && d.buf[d.pos] == '.' {
+		d.pos++
+		integer = false
+
+		if d.digits() == 0 {
+			return nil, false, false
+		}
+	}
+
+	if d.pos < len(d.buf) && (d.buf[d.pos] == 'e' || d.buf[d.pos] == 'E') {
+		d.pos++
+		integer = false
+
+		if d.pos < len(d.buf) && (d.buf[d.pos] == '+' || d.buf[d.pos] == '-') {
+			d.pos++
+		}
+
+		if d.digits() == 0 {
+			return nil, false, false
+		}
+	}
+
+	return d.buf[start:d.pos], integer, true
+}
+
+func (d *stateVectorScanner) digits() int {
+	start := d.pos
+
+	for d.pos < len(d.buf) && d.buf[d.pos] >= '0' && d.buf[d.pos] <= '9' {
+		d.pos++
+	}
+
+	return d.pos - start
+}
+
+func (d *stateVectorScanner) int64() (int64, bool) {
+	raw, integer, ok := d.number()
+
+	if !ok || !integer {
+		return 0, false
+	}
+
+	i, err := strconv.ParseInt(string(raw), 10, 64)
+
+	return i, err == nil
+}
+
+func (d *stateVectorScanner) optionalInt64(v *int64) (null bool, ok bool) {
+	if d.null() {
+		return true, true
+	}
+
+	*v, ok = d.int64()
+
+	return false, ok
+}
+
+func (d *stateVectorScanner) optionalFloat(v *float64) (null bool, ok bool) {
+	if d.null() {
+		return true, true
+	}
+
+	raw, _, ok := d.number()
+
+	if !ok {
+		return false, false
+	}
+
+	f, err := strconv.ParseFloat(string(raw), 64)
+
+	if err != nil {
+		return false, false
+	}
+
+	*v = f
+
+	return false, true
+}
+
+// floatField decodes an optional float followed by a comma, field is pointed to value when it is not null
+func (d *stateVectorScanner) floatField(value *float64, field **float64) bool {
+	null, ok := d.optionalFloat(value)
+
+	if !ok || !d.comma() {
+		return false
+	}
+
+	if !null {
+		*field = value
+	}
+
+	return true
+}
+
+func (d *stateVectorScanner) bool() (bool, bool) {
+	if d.literal("true") {
+		return true, true
+	}
+
+	if d.literal("false") {
+		return false, true
+	}
+
+	return false, false
+}
+
+func (d *stateVectorScanner) optionalBool(v *bool) (null bool, ok bool) {
+	if d.null() {
+		return true, true
+	}
+
+	*v, ok = d.bool()
+
+	return false, ok
+}
+
+func (d *stateVectorScanner) sensors() (*[]int, bool) {
+	if d.null() {
+		return nil, true
+	}
+
+	if !d.consume('[') {
+		return nil, false
+	}
+
+	sensors := []int{}
+
+	if d.consume(']') {
+		return &sensors, true
+	}
+
+	for {
+		sensor, ok := d.int64()
+
+		if !ok {
+			return nil, false
+		}
+
+		sensors = append(sensors, int(sensor))
+
+		if d.consume(']') {
+			return &sensors, true
+		}
+
+		if !d.comma() {
+			return nil, false
+		}
+	}
+}
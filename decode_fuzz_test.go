@@ -0,0 +1,20 @@
+//go:build go1.18
+// +build go1.18
+
+package goflight_test
+
+import (
+	"testing"
+)
+
+func FuzzStateVector_UnmarshalJSON(f *testing.F) {
+	for _, input := range decoderEquivalenceTests {
+		f.Add([]byte(input))
+	}
+
+	for _, state := range readMockStateMessages(f) {
+		f.Add([]byte(state))
+	}
+
+	f.Fuzz(assertDecodersEqual)
+}
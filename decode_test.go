@@ -0,0 +1,111 @@
+package goflight_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// assertDecodersEqual checks that the fast decoder and the encoding/json decoder of StateVector agree on data
+func assertDecodersEqual(t *testing.T, data []byte) {
+	var fast, slow goflight.StateVector
+	fastErr := fast.UnmarshalJSON(data)
+	slowErr := goflight.UnmarshalStateVectorReflect(&slow, data)
+
+	if (fastErr == nil) != (slowErr == nil) {
+		t.Fatalf("expected error %v to equal %v for %q", fastErr, slowErr, data)
+	}
+
+	if fastErr == nil && !reflect.DeepEqual(fast, slow) {
+		t.Fatalf("expected %+v to equal %+v for %q", fast, slow, data)
+	}
+}
+
+var decoderEquivalenceTests = []string{
+	`["484ac1","KLM1234 ","Kingdom of the Netherlands",1586031310,1586031310,4.7,52.3,1000.5,false,120.5,90,-1.3,[1,2],1050.2,"1234",false,0]`,
+	`["484ac1",null,"",null,0,null,null,null,true,null,null,null,null,null,null,null,2]`,
+	`["484ac1",null,"",null,0,null,null,null,true,null,null,null,null,null,null,null,2,4]`,
+	`["484ac1",null,"",null,0,null,null,null,true,null,null,null,null,null,null,null,2,null]`,
+	` [ "484ac1" , "KLM" , "NL" , 1 , 2 , -1e2 , 0.5E-1 , 0 , true , 1 , 2 , 3 , [ ] , 4 , "7700" , true , 1 ] `,
+	`["484ac1","KLM","NL",1,2,1,2,3,false,1,2,3,null,4,null,null,0]`,
+	`["484ac1","KLMé","NL",1,2,1,2,3,false,1,2,3,null,4,null,null,0]`,
+	`["484ac1","\xff","NL",1,2,1,2,3,false,1,2,3,null,4,null,null,0]`,
+	`[null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null,null]`,
+	`["484ac1",null,"",1.0,0,null,null,null,true,null,null,null,null,null,null,null,2]`,
+	`["484ac1",null,"",1e3,0,null,null,null,true,null,null,null,null,null,null,null,2]`,
+	`["484ac1",null,"",null,0,1e400,null,null,true,null,null,null,null,null,null,null,2]`,
+	`["484ac1",null,"",null,0,01,null,null,true,null,null,null,null,null,null,null,2]`,
+	`["484ac1",null,"",null,0,null,null,null,true,null,null,null,[1.5],null,null,null,2]`,
+	`["484ac1",null,"",null,0,null,null,null,true,null,null,null,[1,],null,null,null,2]`,
+	`["484ac1",null,"",null,0,null,null,null,"true",null,null,null,null,null,null,null,2]`,
+	`["484ac1",null,"",null,0,null,null,null,true,null,null,null,null,null,null,null]`,
+	`["484ac1",null,"",null,0,null,null,null,true,null,null,null,null,null,null,null,2,4,5]`,
+	`["484ac1",null,"",null,0,null,null,null,true,null,null,null,null,null,null,null,2] x`,
+	`["484ac1",null,"",null,0,null,null,null,true,null,null,null,null,null,null,null,2`,
+	`{}`,
+	`null`,
+	``,
+}
+
+func TestStateVector_UnmarshalJSON_Equivalence(t *testing.T) {
+	for _, input := range decoderEquivalenceTests {
+		assertDecodersEqual(t, []byte(input))
+	}
+
+	for _, state := range readMockStateMessages(t) {
+		assertDecodersEqual(t, state)
+	}
+}
+
+// readMockStateMessages returns the raw state vector arrays of mocks/states.json
+func readMockStateMessages(tb testing.TB) []json.RawMessage {
+	data, err := ioutil.ReadFile("./mocks/states.json")
+
+	if err != nil {
+		tb.Fatal(err.Error())
+	}
+
+	var response struct {
+		States []json.RawMessage `json:"states"`
+	}
+
+	if err = json.Unmarshal(data, &response); err != nil {
+		tb.Fatal(err.Error())
+	}
+
+	return response.States
+}
+
+func BenchmarkStateVector_UnmarshalJSON(b *testing.B) {
+	states := readMockStateMessages(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, state := range states {
+			var vector goflight.StateVector
+
+			if err := vector.UnmarshalJSON(state); err != nil {
+				b.Fatal(err.Error())
+			}
+		}
+	}
+}
+
+func BenchmarkStateVector_UnmarshalJSON_Reflect(b *testing.B) {
+	states := readMockStateMessages(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, state := range states {
+			var vector goflight.StateVector
+
+			if err := goflight.UnmarshalStateVectorReflect(&vector, state); err != nil {
+				b.Fatal(err.Error())
+			}
+		}
+	}
+}
@@ -13,3 +13,33 @@ var ErrEndBeforeBegin = errors.New("the provided end time is before the begin ti
 
 // ErrTimeRangeTooBig is returned when the time range parameters are too far apart
 var ErrTimeRangeTooBig = errors.New("the provided time range is more than the maximum of 2 hours")
+
+// ErrAirportTimeRangeTooBig is returned when the time range parameters of an airport request are too far apart
+var ErrAirportTimeRangeTooBig = errors.New("the provided time range is more than the maximum of 7 days")
+
+// ErrInvalidGeoJSON is returned when a GeoJSON document can't be used as a geofence
+var ErrInvalidGeoJSON = errors.New("the provided GeoJSON is not a valid polygon geometry")
+
+// ErrMissingPosition is returned when a state vector without latitude and longitude is used in a calculation
+var ErrMissingPosition = errors.New("the state vector has no position")
+
+// ErrMissingVelocity is returned when a state vector without velocity and true track is used in a calculation
+var ErrMissingVelocity = errors.New("the state vector has no velocity or true track")
+
+// ErrTrackNotFound is returned when no track is available for the provided aircraft and time
+var ErrTrackNotFound = errors.New("no track found for the provided aircraft and time")
+
+// ErrOutOfRange is returned when a field has a value outside of its valid range
+var ErrOutOfRange = errors.New("the value is out of range")
+
+// ErrInvalidICAO24 is returned when an ICAO 24-bit address is not 6 hexadecimal digits
+var ErrInvalidICAO24 = errors.New("the icao24 address is not 6 hexadecimal digits")
+
+// ErrInvalidCallsign is returned when a callsign is longer than 8 characters
+var ErrInvalidCallsign = errors.New("the callsign is longer than 8 characters")
+
+// ErrInvalidSquawk is returned when a squawk is not 4 octal digits
+var ErrInvalidSquawk = errors.New("the squawk is not 4 octal digits")
+
+// ErrMissingColumn is returned when a required column is missing from a CSV header
+var ErrMissingColumn = errors.New("a required column is missing")
@@ -2,3 +2,12 @@ package goflight
 
 // SetBaseURL exports the setBaseURL func on client, but only in tests
 var SetBaseURL = (*Client).setBaseURL
+
+// UnmarshalStateVectorReflect exports the encoding/json based decoder of StateVector, but only in tests
+var UnmarshalStateVectorReflect = (*StateVector).unmarshalReflect
+
+// EmbeddedAirports exports the generated airport dataset, but only in tests
+var EmbeddedAirports = embeddedAirports
+
+// EmbeddedAirlines exports the generated airline dataset, but only in tests
+var EmbeddedAirlines = embeddedAirlines
@@ -0,0 +1,104 @@
+package goflight
+
+import (
+	"math"
+	"time"
+)
+
+// Extrapolator projects state vectors forward in time using dead reckoning. Zero values disable the related limit.
+type Extrapolator struct {
+	MaxAge          time.Duration // Source positions older than this are flagged as stale.
+	MaxHorizon      time.Duration // Positions are not projected further than this from the source position.
+	MaxSpeed        float64       // Velocities above this value in m/s are clamped.
+	MaxVerticalRate float64       // Vertical rates above this absolute value in m/s are clamped.
+}
+
+// DefaultExtrapolator contains limits that are reasonable for civil aircraft
+var DefaultExtrapolator = Extrapolator{
+	MaxAge:          time.Second * 30,
+	MaxHorizon:      time.Minute,
+	MaxSpeed:        350,
+	MaxVerticalRate: 40,
+}
+
+// Extrapolation is the projected position of a state vector
+type Extrapolation struct {
+	Position     Point         // Projected position.
+	Altitude     *float64      // Projected barometric altitude in meters, or the geometric altitude if the barometric altitude is unknown. Can be nil.
+	Time         time.Time     // Time the projected position is valid for.
+	Age          time.Duration // Age of the source position at the requested time.
+	Extrapolated bool          // Whether the position was moved. False if velocity or true track are unknown.
+	Clamped      bool          // Whether the projection was limited by MaxHorizon, MaxSpeed or MaxVerticalRate.
+	Stale        bool          // Whether the source position is older than MaxAge and should not be trusted.
+}
+
+// Extrapolate projects the state vector to the provided time using its velocity, true track and vertical rate
+func (e Extrapolator) Extrapolate(s StateVector, t time.Time) (Extrapolation, error) {
+	position, ok := s.Position()
+
+	if !ok {
+		return Extrapolation{}, ErrMissingPosition
+	}
+
+	source := s.positionTime()
+	age := t.Sub(source)
+	result := Extrapolation{
+		Position: position,
+		Time:     t,
+		Age:      age,
+		Stale:    e.MaxAge > 0 && age > e.MaxAge,
+	}
+
+	if altitude := s.altitude(); altitude != nil {
+		a := *altitude
+		result.Altitude = &a
+	}
+
+	if s.Velocity == nil || s.TrueTrack == nil {
+		return result, nil
+	}
+
+	elapsed := age
+
+	if e.MaxHorizon > 0 && (elapsed > e.MaxHorizon || elapsed < -e.MaxHorizon) {
+		elapsed = time.Duration(math.Copysign(float64(e.MaxHorizon), float64(elapsed)))
+		result.Clamped = true
+	}
+
+	velocity := *s.Velocity
+
+	if e.MaxSpeed > 0 && velocity > e.MaxSpeed {
+		velocity = e.MaxSpeed
+		result.Clamped = true
+	}
+
+	seconds := elapsed.Seconds()
+	result.Position = Destination(position, *s.TrueTrack, velocity*seconds)
+	result.Extrapolated = true
+
+	if result.Altitude != nil && s.VerticalRate != nil && !s.OnGround {
+		rate := *s.VerticalRate
+
+		if e.MaxVerticalRate > 0 && math.Abs(rate) > e.MaxVerticalRate {
+			rate = math.Copysign(e.MaxVerticalRate, rate)
+			result.Clamped = true
+		}
+
+		altitude := *result.Altitude + rate*seconds
+
+		if altitude < 0 && *result.Altitude >= 0 {
+			// Descending aircraft don't go underground
+			altitude = 0
+			result.Clamped = true
+		}
+
+		result.Altitude = &altitude
+	}
+
+	return result, nil
+}
+
+// Extrapolate projects the state vector to the provided time using the DefaultExtrapolator
+func (s StateVector) Extrapolate(t time.Time) (Extrapolation, error) {
+	return DefaultExtrapolator.Extrapolate(s, t)
+}
@@ -0,0 +1,66 @@
+package goflight_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestExtrapolator_Extrapolate(t *testing.T) {
+	state := cpaState(52, 5.0, 3000, 100, 90)
+	state.VerticalRate = floatPtr(-10)
+	source := time.Unix(*state.TimePosition, 0)
+
+	result, err := goflight.DefaultExtrapolator.Extrapolate(state, source.Add(time.Second*10))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	origin, _ := state.Position()
+
+	if distance := goflight.Distance(origin, result.Position); math.Abs(distance-1000) > 1 {
+		t.Errorf("expected %v to equal 1000", distance)
+	}
+
+	if math.Abs(*result.Altitude-2900) > 0.001 {
+		t.Errorf("expected %v to equal 2900", *result.Altitude)
+	}
+
+	if result.Stale || result.Clamped || !result.Extrapolated {
+		t.Errorf("unexpected flags on extrapolation: %+v", result)
+	}
+}
+
+func TestExtrapolator_Extrapolate_Limits(t *testing.T) {
+	state := cpaState(52, 5.0, 3000, 100, 90)
+	source := time.Unix(*state.TimePosition, 0)
+
+	result, err := goflight.DefaultExtrapolator.Extrapolate(state, source.Add(time.Minute*5))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	origin, _ := state.Position()
+
+	if distance := goflight.Distance(origin, result.Position); math.Abs(distance-6000) > 1 {
+		t.Errorf("expected %v to be clamped to 6000", distance)
+	}
+
+	if !result.Stale || !result.Clamped {
+		t.Errorf("expected extrapolation to be stale and clamped: %+v", result)
+	}
+
+	if result.Age != time.Minute*5 {
+		t.Errorf("expected %v to equal %v", result.Age, time.Minute*5)
+	}
+
+	_, err = (goflight.StateVector{}).Extrapolate(source)
+
+	if !errors.Is(err, goflight.ErrMissingPosition) {
+		t.Errorf("expected error to be: %v", goflight.ErrMissingPosition.Error())
+	}
+}
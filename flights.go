@@ -2,7 +2,7 @@ package goflight
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -12,6 +12,9 @@ import (
 
 const flightsPrefix string = "/api/flights/"
 
+// maxFlightsInTimeRange is the longest time interval accepted by /api/flights/all
+const maxFlightsInTimeRange = 2 * time.Hour
+
 type flightService struct {
 	client *Client
 }
@@ -32,23 +35,200 @@ type Flight struct {
 	ArrivalAirportCandidatesCount         int64   `json:"arrivalAirportCandidatesCount"`    // Number of other possible departure airports. These are airports in short distance to estArrivalAirport.
 }
 
+// GetFlightsInTime returns the flights of all aircraft within the time interval, from /api/flights/all.
+// The interval can be at most 2 hours.
 func (f *flightService) GetFlightsInTime(begin, end time.Time) ([]Flight, error) {
-	endpoint, err := url.Parse(flightsPrefix + "all")
+	if end.Before(begin) {
+		return nil, ErrEndBeforeBegin
+	}
+
+	if end.Sub(begin) > maxFlightsInTimeRange {
+		return nil, ErrTimeRangeTooBig
+	}
+
+	return f.getFlights("all", url.Values{}, begin, end)
+}
+
+// FlightsInTimeOptions configures how StreamFlightsInTime splits a time interval into requests
+type FlightsInTimeOptions struct {
+	Window  time.Duration // Length of the windows the interval is split into, at most and by default 2 hours.
+	Workers int           // Maximum number of concurrent requests, 1 when zero.
+}
+
+// timeWindow is a part of a time interval
+type timeWindow struct {
+	begin, end time.Time
+}
+
+// flightKey identifies a flight returned by the requests of multiple windows
+type flightKey struct {
+	icao24    string
+	firstSeen int64
+}
+
+// StreamFlightsInTime returns the flights of all aircraft within a time interval of any length, by splitting it
+// into windows which are requested with GetFlightsInTime. The flights are passed to fn in the order of the
+// windows, flights seen in more than one window are passed once. Streaming stops at the first error of a request
+// or fn, which is returned.
+func (f *flightService) StreamFlightsInTime(begin, end time.Time, options FlightsInTimeOptions, fn func(flight Flight) error) error {
+	if end.Before(begin) {
+		return ErrEndBeforeBegin
+	}
+
+	window := options.Window
+
+	if window <= 0 {
+		window = maxFlightsInTimeRange
+	}
+
+	if window > maxFlightsInTimeRange {
+		return ErrTimeRangeTooBig
+	}
+
+	windows := splitTimeRange(begin, end, window)
+	workers := options.Workers
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	if workers > len(windows) {
+		workers = len(windows)
+	}
+
+	type result struct {
+		flights []Flight
+		err     error
+	}
+
+	// Every window has its own buffered channel, so the workers never block and the results can be read in order
+	results := make([]chan result, len(windows))
+
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+
+	jobs := make(chan int)
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		defer close(jobs)
+
+		for i := range windows {
+			select {
+			case jobs <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				flights, err := f.GetFlightsInTime(windows[i].begin, windows[i].end)
+				results[i] <- result{flights: flights, err: err}
+			}
+		}()
+	}
+
+	seen := make(map[flightKey]bool)
+
+	for i := range windows {
+		r := <-results[i]
+
+		if r.err != nil {
+			return r.err
+		}
+
+		for _, flight := range r.flights {
+			key := flightKey{icao24: flight.ICAO24, firstSeen: flight.FirstSeen}
+
+			if seen[key] {
+				continue
+			}
+
+			seen[key] = true
+
+			if err := fn(flight); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetFlightsInTimeRange returns the flights of all aircraft within a time interval of any length, see
+// StreamFlightsInTime
+func (f *flightService) GetFlightsInTimeRange(begin, end time.Time, options FlightsInTimeOptions) ([]Flight, error) {
+	flights := []Flight{}
+	err := f.StreamFlightsInTime(begin, end, options, func(flight Flight) error {
+		flights = append(flights, flight)
+		return nil
+	})
 
 	if err != nil {
 		return nil, err
 	}
 
+	return flights, nil
+}
+
+// splitTimeRange splits the interval into consecutive windows of at most the provided length
+func splitTimeRange(begin, end time.Time, window time.Duration) []timeWindow {
+	var windows []timeWindow
+
+	for {
+		next := begin.Add(window)
+
+		if !next.Before(end) {
+			return append(windows, timeWindow{begin: begin, end: end})
+		}
+
+		windows = append(windows, timeWindow{begin: begin, end: next})
+		begin = next
+	}
+}
+
+// GetArrivalsByAirport returns the flights which arrived at the airport within the time interval, from
+// /api/flights/arrival. The airport is identified by its ICAO code and the interval can be at most 7 days.
+func (f *flightService) GetArrivalsByAirport(airport string, begin, end time.Time) ([]Flight, error) {
+	return f.getAirportFlights("arrival", airport, begin, end)
+}
+
+// GetDeparturesByAirport returns the flights which departed from the airport within the time interval, from
+// /api/flights/departure. The airport is identified by its ICAO code and the interval can be at most 7 days.
+func (f *flightService) GetDeparturesByAirport(airport string, begin, end time.Time) ([]Flight, error) {
+	return f.getAirportFlights("departure", airport, begin, end)
+}
+
+func (f *flightService) getAirportFlights(endpoint, airport string, begin, end time.Time) ([]Flight, error) {
 	if end.Before(begin) {
 		return nil, ErrEndBeforeBegin
 	}
 
-	if end.Sub(begin) > time.Hour*2 {
-		return nil, ErrTimeRangeTooBig
+	if end.Sub(begin) > time.Hour*24*7 {
+		return nil, ErrAirportTimeRangeTooBig
 	}
 
-	u := f.client.baseURL.ResolveReference(endpoint)
 	params := url.Values{}
+	params.Add("airport", airport)
+
+	return f.getFlights(endpoint, params, begin, end)
+}
+
+// getFlights performs the request to the endpoint below /api/flights/ with the time interval added to the
+// parameters. A 404 response means no flights were found.
+func (f *flightService) getFlights(endpoint string, params url.Values, begin, end time.Time) ([]Flight, error) {
+	e, err := url.Parse(flightsPrefix + endpoint)
+
+	if err != nil {
+		return nil, err
+	}
+
+	u := f.client.baseURL.ResolveReference(e)
 	params.Add("begin", strconv.FormatInt(begin.Unix(), 10))
 	params.Add("end", strconv.FormatInt(end.Unix(), 10))
 	u.RawQuery = params.Encode()
@@ -72,12 +252,14 @@ func (f *flightService) GetFlightsInTime(begin, end time.Time) ([]Flight, error)
 		return nil, err
 	}
 
+	defer resp.Body.Close()
+
 	if resp.StatusCode/100 != 2 {
 		if resp.StatusCode == 404 {
 			return []Flight{}, nil
 		}
 
-		return nil, fmt.Errorf(resp.Status)
+		return nil, errors.New(resp.Status)
 	}
 
 	data, err := ioutil.ReadAll(resp.Body)
@@ -86,8 +268,6 @@ func (f *flightService) GetFlightsInTime(begin, end time.Time) ([]Flight, error)
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-
 	var result []Flight
 
 	if err = json.Unmarshal(data, &result); err != nil {
@@ -3,9 +3,13 @@ package goflight_test
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/marcelblijleven/goflight"
 	"io/ioutil"
+	"net/http"
 	"net/url"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -173,3 +177,183 @@ func TestFlightService_GetFlightsInTime(t *testing.T) {
 		})
 	}
 }
+
+func TestFlightService_GetByAirport(t *testing.T) {
+	body, err := ioutil.ReadFile("./mocks/flights.json")
+
+	if err != nil {
+		t.Fatal("unexpected error while reading mock file flights.json")
+	}
+
+	var requests []*url.URL
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL)
+		w.Write(body)
+	})
+	mockHTTPClient, closeServer := HTTPTestClient(mockHandler)
+	defer closeServer()
+
+	client, err := goflight.NewClient("", "", mockHTTPClient)
+
+	if err != nil {
+		t.Fatal("unexpected error while creating new Gofight client")
+	}
+
+	u, _ := url.Parse("http://example.com")
+	goflight.SetBaseURL(client, u)
+	begin := time.Unix(1517227200, 0)
+	end := begin.Add(24 * time.Hour)
+
+	arrivals, err := client.Flights.GetArrivalsByAirport("EDDF", begin, end)
+
+	if err != nil || len(arrivals) != 2 {
+		t.Errorf("unexpected arrivals %v and error %v", arrivals, err)
+	}
+
+	departures, err := client.Flights.GetDeparturesByAirport("EDDF", begin, end)
+
+	if err != nil || len(departures) != 2 {
+		t.Errorf("unexpected departures %v and error %v", departures, err)
+	}
+
+	for i, path := range []string{"/api/flights/arrival", "/api/flights/departure"} {
+		query := requests[i].Query()
+
+		if requests[i].Path != path || query.Get("airport") != "EDDF" || query.Get("begin") != "1517227200" || query.Get("end") != "1517313600" {
+			t.Errorf("unexpected request %v", requests[i])
+		}
+	}
+
+	if _, err = client.Flights.GetArrivalsByAirport("EDDF", begin, begin.Add(8*24*time.Hour)); err != goflight.ErrAirportTimeRangeTooBig {
+		t.Errorf("expected %v to equal %v", err, goflight.ErrAirportTimeRangeTooBig)
+	}
+
+	if _, err = client.Flights.GetDeparturesByAirport("EDDF", end, begin); err != goflight.ErrEndBeforeBegin {
+		t.Errorf("expected %v to equal %v", err, goflight.ErrEndBeforeBegin)
+	}
+
+	if len(requests) != 2 {
+		t.Errorf("expected %v requests to equal 2", len(requests))
+	}
+}
+
+// chunkedFlightsHandler returns a flight per request starting at begin, and a flight seen in every request
+func chunkedFlightsHandler(t *testing.T, failBegin string, active, maxActive *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if n := atomic.AddInt32(active, 1); n > atomic.LoadInt32(maxActive) {
+			atomic.StoreInt32(maxActive, n)
+		}
+
+		defer atomic.AddInt32(active, -1)
+		time.Sleep(5 * time.Millisecond)
+		query := r.URL.Query()
+		begin, end := query.Get("begin"), query.Get("end")
+
+		if begin == failBegin {
+			w.WriteHeader(500)
+			return
+		}
+
+		b, _ := strconv.ParseInt(begin, 10, 64)
+
+		if e, _ := strconv.ParseInt(end, 10, 64); e-b > 7200 {
+			t.Errorf("expected the window %v - %v to be at most 2 hours", begin, end)
+		}
+
+		fmt.Fprintf(w, `[{"icao24": "abc123", "firstSeen": %v}, {"icao24": "def456", "firstSeen": 1}]`, begin)
+	}
+}
+
+func TestFlightService_StreamFlightsInTime(t *testing.T) {
+	begin := time.Unix(1517227200, 0)
+	end := begin.Add(24*time.Hour + time.Minute)
+
+	for _, workers := range []int{0, 1, 4, 100} {
+		t.Run(strconv.Itoa(workers), func(t *testing.T) {
+			var active, maxActive int32
+			mockHTTPClient, closeServer := HTTPTestClient(chunkedFlightsHandler(t, "", &active, &maxActive))
+			defer closeServer()
+
+			client, _ := goflight.NewClient("", "", mockHTTPClient)
+			u, _ := url.Parse("http://example.com")
+			goflight.SetBaseURL(client, u)
+
+			var flights []goflight.Flight
+			err := client.Flights.StreamFlightsInTime(begin, end, goflight.FlightsInTimeOptions{Workers: workers}, func(flight goflight.Flight) error {
+				flights = append(flights, flight)
+				return nil
+			})
+
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			// 13 windows with a flight each, and the flight seen in every window once
+			if len(flights) != 14 {
+				t.Fatalf("expected %v flights to equal 14", len(flights))
+			}
+
+			expected := []int64{begin.Unix(), 1}
+
+			for i := 1; i < 13; i++ {
+				expected = append(expected, begin.Add(time.Duration(i)*2*time.Hour).Unix())
+			}
+
+			for i, flight := range flights {
+				if flight.FirstSeen != expected[i] {
+					t.Errorf("expected first seen %v to equal %v", flight.FirstSeen, expected[i])
+				}
+			}
+
+			if limit := int32(workers); limit > 1 && maxActive > limit || limit <= 1 && maxActive != 1 {
+				t.Errorf("unexpected maximum of %v concurrent requests for %v workers", maxActive, workers)
+			}
+		})
+	}
+}
+
+func TestFlightService_StreamFlightsInTime_Error(t *testing.T) {
+	begin := time.Unix(1517227200, 0)
+	end := begin.Add(24 * time.Hour)
+	var active, maxActive int32
+	failBegin := strconv.FormatInt(begin.Add(4*time.Hour).Unix(), 10)
+	mockHTTPClient, closeServer := HTTPTestClient(chunkedFlightsHandler(t, failBegin, &active, &maxActive))
+	defer closeServer()
+
+	client, _ := goflight.NewClient("", "", mockHTTPClient)
+	u, _ := url.Parse("http://example.com")
+	goflight.SetBaseURL(client, u)
+	options := goflight.FlightsInTimeOptions{Window: time.Hour, Workers: 3}
+
+	flights, err := client.Flights.GetFlightsInTimeRange(begin, end, options)
+
+	if err == nil || err.Error() != "500 Internal Server Error" || flights != nil {
+		t.Errorf("unexpected flights %v and error %v", flights, err)
+	}
+
+	errStop := errors.New("stop")
+	count := 0
+	err = client.Flights.StreamFlightsInTime(begin, end, options, func(flight goflight.Flight) error {
+		if count++; count == 2 {
+			return errStop
+		}
+
+		return nil
+	})
+
+	if err != errStop || count != 2 {
+		t.Errorf("expected %v after %v flights to equal %v after 2", err, count, errStop)
+	}
+
+	if _, err = client.Flights.GetFlightsInTimeRange(begin, end, goflight.FlightsInTimeOptions{Window: 3 * time.Hour}); err != goflight.ErrTimeRangeTooBig {
+		t.Errorf("expected %v to equal %v", err, goflight.ErrTimeRangeTooBig)
+	}
+
+	if _, err = client.Flights.GetFlightsInTimeRange(end, begin, options); err != goflight.ErrEndBeforeBegin {
+		t.Errorf("expected %v to equal %v", err, goflight.ErrEndBeforeBegin)
+	}
+
+	if flights, err = client.Flights.GetFlightsInTimeRange(begin, begin, options); err != nil || len(flights) != 2 {
+		t.Errorf("unexpected flights %v and error %v for an empty interval", flights, err)
+	}
+}
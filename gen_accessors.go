@@ -0,0 +1,178 @@
+//go:build ignore
+// +build ignore
+
+// This program generates accessors.go. It is invoked by running go generate.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+)
+
+type field struct {
+	name     string
+	typ      string
+	optional bool
+}
+
+type structType struct {
+	name     string
+	file     string
+	receiver string
+	fields   []field
+}
+
+var structTypes = []*structType{
+	{name: "StateVector", file: "states.go", receiver: "s"},
+	{name: "Flight", file: "flights.go", receiver: "f"},
+}
+
+func main() {
+	fset := token.NewFileSet()
+
+	for _, st := range structTypes {
+		f, err := parser.ParseFile(fset, st.file, nil, 0)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		st.fields = structFields(fset, f, st.name)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_accessors.go; DO NOT EDIT.\n\npackage goflight\n")
+
+	for _, st := range structTypes {
+		writeStruct(&buf, st)
+	}
+
+	src, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile("accessors.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func structFields(fset *token.FileSet, f *ast.File, name string) []field {
+	var fields []field
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.TypeSpec)
+
+		if !ok || spec.Name.Name != name {
+			return true
+		}
+
+		for _, fl := range spec.Type.(*ast.StructType).Fields.List {
+			var typ bytes.Buffer
+			expr := fl.Type
+			star, optional := expr.(*ast.StarExpr)
+
+			if optional {
+				expr = star.X
+			}
+
+			if err := format.Node(&typ, fset, expr); err != nil {
+				log.Fatal(err)
+			}
+
+			for _, ident := range fl.Names {
+				fields = append(fields, field{name: ident.Name, typ: typ.String(), optional: optional})
+			}
+		}
+
+		return false
+	})
+
+	if len(fields) == 0 {
+		log.Fatalf("struct %s not found", name)
+	}
+
+	return fields
+}
+
+func writeStruct(buf *bytes.Buffer, st *structType) {
+	r := st.receiver
+	flat := st.name + "Flat"
+	mask := st.name + "Field"
+
+	for _, fl := range st.fields {
+		if !fl.optional {
+			continue
+		}
+
+		fmt.Fprintf(buf, "\n// Get%[1]s returns the value of %[1]s, ok is false if it is nil\n", fl.name)
+		fmt.Fprintf(buf, "func (%s %s) Get%s() (value %s, ok bool) {\n", r, st.name, fl.name, fl.typ)
+		fmt.Fprintf(buf, "if %s.%s == nil {\nreturn value, false\n}\n\n", r, fl.name)
+		fmt.Fprintf(buf, "return *%s.%s, true\n}\n", r, fl.name)
+	}
+
+	fmt.Fprintf(buf, "\n// %s is a bitmask of the optional fields of a %s\n", mask, st.name)
+	fmt.Fprintf(buf, "type %s uint32\n\n", mask)
+	buf.WriteString("// Optional fields which can be present in a " + flat + "\nconst (\n")
+
+	first := true
+
+	for _, fl := range st.fields {
+		if !fl.optional {
+			continue
+		}
+
+		if first {
+			fmt.Fprintf(buf, "%s%s %s = 1 << iota\n", mask, fl.name, mask)
+			first = false
+			continue
+		}
+
+		fmt.Fprintf(buf, "%s%s\n", mask, fl.name)
+	}
+
+	buf.WriteString(")\n")
+
+	fmt.Fprintf(buf, "\n// %s is a value-type view of a %s. Nil fields hold the zero value and are not set in Present.\n", flat, st.name)
+	fmt.Fprintf(buf, "type %s struct {\n", flat)
+
+	for _, fl := range st.fields {
+		fmt.Fprintf(buf, "%s %s\n", fl.name, fl.typ)
+	}
+
+	fmt.Fprintf(buf, "Present %s\n}\n", mask)
+
+	fmt.Fprintf(buf, "\n// Has returns true when all provided fields were present in the %s\n", st.name)
+	fmt.Fprintf(buf, "func (v %s) Has(fields %s) bool {\nreturn v.Present&fields == fields\n}\n", flat, mask)
+
+	fmt.Fprintf(buf, "\n// Flat returns a value-type view of the %s\n", st.name)
+	fmt.Fprintf(buf, "func (%s %s) Flat() %s {\n", r, st.name, flat)
+	fmt.Fprintf(buf, "v := %s{\n", flat)
+
+	for _, fl := range st.fields {
+		if !fl.optional {
+			fmt.Fprintf(buf, "%s: %s.%s,\n", fl.name, r, fl.name)
+		}
+	}
+
+	buf.WriteString("}\n")
+
+	for _, fl := range st.fields {
+		if !fl.optional {
+			continue
+		}
+
+		fmt.Fprintf(buf, "\nif %s.%s != nil {\n", r, fl.name)
+		fmt.Fprintf(buf, "v.%s = *%s.%s\n", fl.name, r, fl.name)
+		fmt.Fprintf(buf, "v.Present |= %s%s\n}\n", mask, fl.name)
+	}
+
+	buf.WriteString("\nreturn v\n}\n")
+}
@@ -0,0 +1,64 @@
+//go:build ignore
+// +build ignore
+
+// This program generates data.go from the datasets in the data directory, so they are available on every Go
+// version. It is invoked by running go generate.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"strings"
+)
+
+type dataset struct {
+	name string
+	file string
+}
+
+var datasets = []dataset{
+	{name: "embeddedAirports", file: "data/airports.csv"},
+	{name: "embeddedAirlines", file: "data/airlines.csv"},
+}
+
+func main() {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_data.go; DO NOT EDIT.\n\npackage goflight\n")
+
+	for _, d := range datasets {
+		data, err := ioutil.ReadFile(d.file)
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		lines := strings.SplitAfter(string(data), "\n")
+
+		if lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+
+		fmt.Fprintf(&buf, "\n// %s is the content of %s\n", d.name, d.file)
+		fmt.Fprintf(&buf, "var %s = %s", d.name, strconv.Quote(lines[0]))
+
+		for _, line := range lines[1:] {
+			fmt.Fprintf(&buf, " +\n%s", strconv.Quote(line))
+		}
+
+		buf.WriteString("\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile("data.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
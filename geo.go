@@ -0,0 +1,252 @@
+package goflight
+
+import (
+	"math"
+	"time"
+)
+
+// earthRadius is the mean radius of the earth in meters
+const earthRadius = 6371008.8
+
+// Point represents a WGS-84 position in decimal degrees
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// ClosestApproach is the estimated closest point of approach of two aircraft
+type ClosestApproach struct {
+	Time               time.Time     // Estimated time of the closest approach.
+	TimeToCPA          time.Duration // Time from the most recent position report until the closest approach. Zero when the aircraft are diverging.
+	HorizontalDistance float64       // Horizontal distance between the aircraft at the closest approach in meters.
+	VerticalDistance   float64       // Absolute vertical distance between the aircraft at the closest approach in meters. Zero if an altitude is unknown.
+	Distance           float64       // Slant distance between the aircraft at the closest approach in meters.
+	HasAltitude        bool          // Whether the altitude of both aircraft was known and used in the estimation.
+	PositionA          Point         // Estimated position of the first aircraft at the closest approach.
+	PositionB          Point         // Estimated position of the second aircraft at the closest approach.
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// Distance returns the great-circle distance between two points in meters, using the haversine formula
+func Distance(a, b Point) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLat := lat2 - lat1
+	dLon := radians(b.Longitude - a.Longitude)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// InitialBearing returns the initial great-circle bearing from a to b in decimal degrees clockwise from north
+func InitialBearing(a, b Point) float64 {
+	lat1, lat2 := radians(a.Latitude), radians(b.Latitude)
+	dLon := radians(b.Longitude - a.Longitude)
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the point reached when travelling the distance in meters from p along the initial bearing
+func Destination(p Point, bearing, distance float64) Point {
+	lat1, lon1 := radians(p.Latitude), radians(p.Longitude)
+	theta := radians(bearing)
+	delta := distance / earthRadius
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+
+	return Point{
+		Latitude:  degrees(lat2),
+		Longitude: math.Mod(degrees(lon2)+540, 360) - 180,
+	}
+}
+
+// BoundingBoxAround returns the smallest bounding box containing the circle of radius meters around p. ok is
+// false when the circle contains a pole or crosses the antimeridian, which a single bounding box can't describe.
+func BoundingBoxAround(p Point, radius float64) (box BoundingBox, ok bool) {
+	delta := radius / earthRadius
+	lat := radians(p.Latitude)
+
+	if lat+delta >= math.Pi/2 || lat-delta <= -math.Pi/2 {
+		return BoundingBox{}, false
+	}
+
+	// The longitude difference is largest where the great circle through the pole touches the circle
+	dLon := degrees(math.Asin(math.Sin(delta) / math.Cos(lat)))
+	box = BoundingBox{
+		LaMin: p.Latitude - degrees(delta),
+		LoMin: p.Longitude - dLon,
+		LaMax: p.Latitude + degrees(delta),
+		LoMax: p.Longitude + dLon,
+	}
+
+	if box.LoMin < -180 || box.LoMax > 180 {
+		return BoundingBox{}, false
+	}
+
+	return box, true
+}
+
+// Position returns the position of the state vector, ok is false when no position was received
+func (s StateVector) Position() (p Point, ok bool) {
+	if s.Latitude == nil || s.Longitude == nil {
+		return Point{}, false
+	}
+
+	return Point{Latitude: *s.Latitude, Longitude: *s.Longitude}, true
+}
+
+// DistanceTo returns the great-circle distance in meters from the state vector to the point
+func (s StateVector) DistanceTo(p Point) (distance float64, ok bool) {
+	position, ok := s.Position()
+
+	if !ok {
+		return 0, false
+	}
+
+	return Distance(position, p), true
+}
+
+// BearingTo returns the initial bearing in decimal degrees from the state vector to the point
+func (s StateVector) BearingTo(p Point) (bearing float64, ok bool) {
+	position, ok := s.Position()
+
+	if !ok {
+		return 0, false
+	}
+
+	return InitialBearing(position, p), true
+}
+
+// DistanceBetween returns the great-circle distance in meters between two state vectors
+func DistanceBetween(a, b StateVector) (distance float64, ok bool) {
+	position, ok := b.Position()
+
+	if !ok {
+		return 0, false
+	}
+
+	return a.DistanceTo(position)
+}
+
+// BearingBetween returns the initial bearing in decimal degrees from state vector a to state vector b
+func BearingBetween(a, b StateVector) (bearing float64, ok bool) {
+	position, ok := b.Position()
+
+	if !ok {
+		return 0, false
+	}
+
+	return a.BearingTo(position)
+}
+
+// positionTime returns the time of the position report, falling back to the last contact
+func (s StateVector) positionTime() time.Time {
+	if s.TimePosition != nil {
+		return time.Unix(*s.TimePosition, 0)
+	}
+
+	return time.Unix(s.LastContact, 0)
+}
+
+// altitude returns the barometric altitude, falling back to the geometric altitude
+func (s StateVector) altitude() *float64 {
+	if s.BaroAltitude != nil {
+		return s.BaroAltitude
+	}
+
+	return s.GeoAltitude
+}
+
+// ClosestPointOfApproach estimates when and where two aircraft will be closest to each other, assuming both keep
+// their current velocity, true track and vertical rate. Positions are projected onto a local plane, which is
+// accurate for aircraft within a few hundred kilometers of each other.
+func ClosestPointOfApproach(a, b StateVector) (ClosestApproach, error) {
+	posA, okA := a.Position()
+	posB, okB := b.Position()
+
+	if !okA || !okB {
+		return ClosestApproach{}, ErrMissingPosition
+	}
+
+	if a.Velocity == nil || a.TrueTrack == nil || b.Velocity == nil || b.TrueTrack == nil {
+		return ClosestApproach{}, ErrMissingVelocity
+	}
+
+	// Both positions are brought to the time of the most recent position report
+	timeA, timeB := a.positionTime(), b.positionTime()
+	reference := timeA
+
+	if timeB.After(reference) {
+		reference = timeB
+	}
+
+	cosLat := math.Cos(radians(posA.Latitude))
+	ax, ay, az := 0.0, 0.0, 0.0
+	// The longitude difference is wrapped, so aircraft on either side of the antimeridian are close together
+	bx := radians(math.Remainder(posB.Longitude-posA.Longitude, 360)) * cosLat * earthRadius
+	by := radians(posB.Latitude-posA.Latitude) * earthRadius
+	bz := 0.0
+
+	avx, avy, avz := velocityComponents(a)
+	bvx, bvy, bvz := velocityComponents(b)
+
+	altA, altB := a.altitude(), b.altitude()
+	hasAltitude := altA != nil && altB != nil
+
+	if hasAltitude {
+		az, bz = *altA, *altB
+	} else {
+		avz, bvz = 0, 0
+	}
+
+	lagA, lagB := reference.Sub(timeA).Seconds(), reference.Sub(timeB).Seconds()
+	ax, ay, az = ax+avx*lagA, ay+avy*lagA, az+avz*lagA
+	bx, by, bz = bx+bvx*lagB, by+bvy*lagB, bz+bvz*lagB
+
+	dx, dy, dz := bx-ax, by-ay, bz-az
+	dvx, dvy, dvz := bvx-avx, bvy-avy, bvz-avz
+
+	t := 0.0
+
+	if dv2 := dvx*dvx + dvy*dvy + dvz*dvz; dv2 > 0 {
+		t = math.Max(0, -(dx*dvx+dy*dvy+dz*dvz)/dv2)
+	}
+
+	hx, hy, vz := dx+dvx*t, dy+dvy*t, math.Abs(dz+dvz*t)
+	horizontal := math.Hypot(hx, hy)
+
+	return ClosestApproach{
+		Time:               reference.Add(time.Duration(t * float64(time.Second))),
+		TimeToCPA:          time.Duration(t * float64(time.Second)),
+		HorizontalDistance: horizontal,
+		VerticalDistance:   vz,
+		Distance:           math.Hypot(horizontal, vz),
+		HasAltitude:        hasAltitude,
+		PositionA:          Destination(posA, *a.TrueTrack, *a.Velocity*(lagA+t)),
+		PositionB:          Destination(posB, *b.TrueTrack, *b.Velocity*(lagB+t)),
+	}, nil
+}
+
+// velocityComponents returns the east, north and up velocity of the state vector in m/s
+func velocityComponents(s StateVector) (east, north, up float64) {
+	track := radians(*s.TrueTrack)
+	east = *s.Velocity * math.Sin(track)
+	north = *s.Velocity * math.Cos(track)
+
+	if s.VerticalRate != nil {
+		up = *s.VerticalRate
+	}
+
+	return east, north, up
+}
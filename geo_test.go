@@ -0,0 +1,163 @@
+package goflight_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"math"
+	"testing"
+	"time"
+)
+
+var (
+	amsterdam = goflight.Point{Latitude: 52.3086, Longitude: 4.7639}
+	london    = goflight.Point{Latitude: 51.4706, Longitude: -0.4619}
+)
+
+func TestDistance(t *testing.T) {
+	distance := goflight.Distance(amsterdam, london)
+
+	if math.Abs(distance-370000) > 5000 {
+		t.Errorf("expected %v to be approximately 370 km", distance)
+	}
+
+	if distance := goflight.Distance(amsterdam, amsterdam); distance != 0 {
+		t.Errorf("expected %v to equal 0", distance)
+	}
+}
+
+func TestInitialBearing(t *testing.T) {
+	bearing := goflight.InitialBearing(amsterdam, london)
+
+	if math.Abs(bearing-257.5) > 1 {
+		t.Errorf("expected %v to be approximately 257.5 degrees", bearing)
+	}
+}
+
+func TestDestination(t *testing.T) {
+	bearing := goflight.InitialBearing(amsterdam, london)
+	destination := goflight.Destination(amsterdam, bearing, goflight.Distance(amsterdam, london))
+
+	if goflight.Distance(destination, london) > 1 {
+		t.Errorf("expected %v to equal %v", destination, london)
+	}
+}
+
+func TestBoundingBoxAround(t *testing.T) {
+	box, ok := goflight.BoundingBoxAround(amsterdam, 100000)
+
+	if !ok {
+		t.Fatal("expected a bounding box around amsterdam")
+	}
+
+	// The box touches the circle in the north and south, and at its widest point in the east and west
+	north := goflight.Point{Latitude: box.LaMax, Longitude: amsterdam.Longitude}
+	east := goflight.Destination(amsterdam, 90, 100000)
+
+	if d := goflight.Distance(amsterdam, north); math.Abs(d-100000) > 1 {
+		t.Errorf("expected %v to be 100 km", d)
+	}
+
+	if box.LoMax < east.Longitude || box.LoMax-east.Longitude > 0.01 {
+		t.Errorf("expected %v to be slightly east of %v", box.LoMax, east.Longitude)
+	}
+
+	if center := (box.LoMin + box.LoMax) / 2; math.Abs(center-amsterdam.Longitude) > 1e-9 {
+		t.Errorf("expected the box to be centered on %v, got %v", amsterdam.Longitude, center)
+	}
+
+	if _, ok = goflight.BoundingBoxAround(goflight.Point{Latitude: 89.5, Longitude: 0}, 100000); ok {
+		t.Error("expected no bounding box around a circle containing the pole")
+	}
+
+	if _, ok = goflight.BoundingBoxAround(goflight.Point{Latitude: -16.9, Longitude: -179.5}, 100000); ok {
+		t.Error("expected no bounding box around a circle crossing the antimeridian")
+	}
+}
+
+func TestStateVector_DistanceTo(t *testing.T) {
+	state := goflight.StateVector{Latitude: floatPtr(amsterdam.Latitude), Longitude: floatPtr(amsterdam.Longitude)}
+
+	if _, ok := state.DistanceTo(london); !ok {
+		t.Error("expected distance to be available")
+	}
+
+	if _, ok := (goflight.StateVector{}).DistanceTo(london); ok {
+		t.Error("expected distance to be unavailable without a position")
+	}
+}
+
+func cpaState(lat, lon, altitude, velocity, track float64) goflight.StateVector {
+	timePosition := int64(1586031309)
+
+	return goflight.StateVector{
+		TimePosition: &timePosition,
+		Latitude:     floatPtr(lat),
+		Longitude:    floatPtr(lon),
+		BaroAltitude: floatPtr(altitude),
+		Velocity:     floatPtr(velocity),
+		TrueTrack:    floatPtr(track),
+	}
+}
+
+func TestClosestPointOfApproach(t *testing.T) {
+	// Two aircraft 0.2 degrees of longitude apart flying head-on at 100 m/s, 300 m vertical separation
+	a := cpaState(52, 5.0, 3000, 100, 90)
+	b := cpaState(52, 5.2, 3300, 100, 270)
+
+	cpa, err := goflight.ClosestPointOfApproach(a, b)
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	separation, _ := goflight.DistanceBetween(a, b)
+	expected := time.Duration(separation / 200 * float64(time.Second))
+
+	if diff := cpa.TimeToCPA - expected; diff > time.Second || diff < -time.Second {
+		t.Errorf("expected %v to equal %v", cpa.TimeToCPA, expected)
+	}
+
+	if cpa.HorizontalDistance > 1 {
+		t.Errorf("expected %v to be approximately 0", cpa.HorizontalDistance)
+	}
+
+	if math.Abs(cpa.VerticalDistance-300) > 0.001 || !cpa.HasAltitude {
+		t.Errorf("expected %v to equal 300", cpa.VerticalDistance)
+	}
+
+	if goflight.Distance(cpa.PositionA, cpa.PositionB) > 50 {
+		t.Errorf("expected %v and %v to be close", cpa.PositionA, cpa.PositionB)
+	}
+
+	// Diverging aircraft are closest right now
+	cpa, err = goflight.ClosestPointOfApproach(cpaState(52, 5.0, 3000, 100, 270), cpaState(52, 5.2, 3000, 100, 90))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if cpa.TimeToCPA != 0 {
+		t.Errorf("expected %v to equal 0", cpa.TimeToCPA)
+	}
+
+	// Head-on across the antimeridian, 0.2 degrees of longitude apart
+	across, err := goflight.ClosestPointOfApproach(cpaState(52, 179.9, 3000, 100, 90), cpaState(52, -179.9, 3300, 100, 270))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if diff := across.TimeToCPA - expected; diff > time.Second || diff < -time.Second || across.HorizontalDistance > 1 {
+		t.Errorf("expected %v and %v to equal %v and 0", across.TimeToCPA, across.HorizontalDistance, expected)
+	}
+
+	if goflight.Distance(across.PositionA, across.PositionB) > 50 {
+		t.Errorf("expected %v and %v to be close", across.PositionA, across.PositionB)
+	}
+
+	_, err = goflight.ClosestPointOfApproach(a, goflight.StateVector{})
+
+	if !errors.Is(err, goflight.ErrMissingPosition) {
+		t.Errorf("expected error to be: %v", goflight.ErrMissingPosition.Error())
+	}
+}
@@ -0,0 +1,409 @@
+package goflight
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// GeofenceEventType describes the kind of transition reported by a GeofenceEngine
+type GeofenceEventType int
+
+const (
+	// GeofenceEnter is emitted when an aircraft is first seen inside a geofence
+	GeofenceEnter GeofenceEventType = iota
+	// GeofenceExit is emitted when an aircraft leaves a geofence or is no longer seen
+	GeofenceExit
+	// GeofenceDwell is emitted once when an aircraft has stayed inside a geofence for the dwell time
+	GeofenceDwell
+)
+
+// String returns the name of the event type
+func (t GeofenceEventType) String() string {
+	switch t {
+	case GeofenceEnter:
+		return "enter"
+	case GeofenceExit:
+		return "exit"
+	case GeofenceDwell:
+		return "dwell"
+	}
+
+	return fmt.Sprintf("GeofenceEventType(%d)", int(t))
+}
+
+// Ring is a closed line of [longitude, latitude] positions, in GeoJSON order
+type Ring [][2]float64
+
+// Polygon is a GeoJSON polygon. The first ring is the exterior, any following rings are holes.
+type Polygon []Ring
+
+// Geofence represents a named area, optionally limited to an altitude band
+type Geofence struct {
+	ID             string    // Identifier of the geofence, taken from the GeoJSON feature id or name property.
+	Polygons       []Polygon // Areas covered by the geofence. A position inside any of the polygons is inside the geofence.
+	MinAltitude    *float64  // Lower bound of the altitude band in meters. Can be nil for no lower bound.
+	MaxAltitude    *float64  // Upper bound of the altitude band in meters. Can be nil for no upper bound.
+	UseGeoAltitude bool      // Use GeoAltitude instead of BaroAltitude when checking the altitude band.
+}
+
+// GeofenceEvent is emitted by a GeofenceEngine when an aircraft enters, exits or dwells in a geofence
+type GeofenceEvent struct {
+	Type     GeofenceEventType
+	FenceID  string
+	ICAO24   string
+	Time     time.Time     // Time of the snapshot which triggered the event.
+	Duration time.Duration // Time spent inside the geofence. Zero for enter events.
+	State    StateVector   // Last known state of the aircraft.
+}
+
+type geofenceGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+type geofenceFeature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id"`
+	Geometry   *geofenceGeometry      `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+	Features   []geofenceFeature      `json:"features"`
+
+	// Set when the document is a bare geometry
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// ParseGeofences parses a GeoJSON document into geofences. The document can be a Polygon or MultiPolygon
+// geometry, a Feature or a FeatureCollection. Features can set an altitude band in meters
+// with the minAltitude and maxAltitude properties.
+func ParseGeofences(data []byte) ([]Geofence, error) {
+	var doc geofenceFeature
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	switch doc.Type {
+	case "FeatureCollection":
+		fences := make([]Geofence, 0, len(doc.Features))
+
+		for i, feature := range doc.Features {
+			fence, err := parseGeofenceFeature(feature, fmt.Sprint(i))
+
+			if err != nil {
+				return nil, err
+			}
+
+			fences = append(fences, fence)
+		}
+
+		return fences, nil
+	case "Feature":
+		fence, err := parseGeofenceFeature(doc, "0")
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []Geofence{fence}, nil
+	case "Polygon", "MultiPolygon":
+		polygons, err := parseGeofenceGeometry(geofenceGeometry{Type: doc.Type, Coordinates: doc.Coordinates})
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []Geofence{{ID: "0", Polygons: polygons}}, nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported type %q", ErrInvalidGeoJSON, doc.Type)
+}
+
+func parseGeofenceFeature(feature geofenceFeature, defaultID string) (Geofence, error) {
+	if feature.Geometry == nil {
+		return Geofence{}, fmt.Errorf("%w: feature without geometry", ErrInvalidGeoJSON)
+	}
+
+	polygons, err := parseGeofenceGeometry(*feature.Geometry)
+
+	if err != nil {
+		return Geofence{}, err
+	}
+
+	fence := Geofence{ID: defaultID, Polygons: polygons}
+
+	if name, ok := feature.Properties["name"].(string); ok && name != "" {
+		fence.ID = name
+	}
+
+	if feature.ID != nil {
+		fence.ID = fmt.Sprint(feature.ID)
+	}
+
+	if min, ok := feature.Properties["minAltitude"].(float64); ok {
+		fence.MinAltitude = &min
+	}
+
+	if max, ok := feature.Properties["maxAltitude"].(float64); ok {
+		fence.MaxAltitude = &max
+	}
+
+	return fence, nil
+}
+
+func parseGeofenceGeometry(geometry geofenceGeometry) ([]Polygon, error) {
+	var polygons []Polygon
+
+	switch geometry.Type {
+	case "Polygon":
+		var polygon Polygon
+
+		if err := json.Unmarshal(geometry.Coordinates, &polygon); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+		}
+
+		polygons = []Polygon{polygon}
+	case "MultiPolygon":
+		if err := json.Unmarshal(geometry.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidGeoJSON, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported geometry type %q", ErrInvalidGeoJSON, geometry.Type)
+	}
+
+	for _, polygon := range polygons {
+		if len(polygon) == 0 {
+			return nil, fmt.Errorf("%w: polygon without rings", ErrInvalidGeoJSON)
+		}
+
+		for _, ring := range polygon {
+			if len(ring) < 4 {
+				return nil, fmt.Errorf("%w: polygon ring with less than 4 positions", ErrInvalidGeoJSON)
+			}
+		}
+	}
+
+	return polygons, nil
+}
+
+// ContainsPosition returns true when the provided position is inside one of the polygons of the geofence.
+// The altitude band is not taken into account.
+func (g Geofence) ContainsPosition(latitude, longitude float64) bool {
+	for _, polygon := range g.Polygons {
+		if polygon.contains(longitude, latitude) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Contains returns true when the state vector is inside the geofence, including its altitude band.
+// A state vector without position, or without altitude when an altitude band is set, is never inside.
+func (g Geofence) Contains(s StateVector) bool {
+	position, ok := s.Position()
+
+	if !ok {
+		return false
+	}
+
+	if g.MinAltitude != nil || g.MaxAltitude != nil {
+		altitude := s.BaroAltitude
+
+		if g.UseGeoAltitude {
+			altitude = s.GeoAltitude
+		}
+
+		if altitude == nil {
+			return false
+		}
+
+		if g.MinAltitude != nil && *altitude < *g.MinAltitude {
+			return false
+		}
+
+		if g.MaxAltitude != nil && *altitude > *g.MaxAltitude {
+			return false
+		}
+	}
+
+	return g.ContainsPosition(position.Latitude, position.Longitude)
+}
+
+// contains checks if the point is inside the exterior ring and outside the holes. A polygon without rings is
+// empty and contains no points.
+func (p Polygon) contains(x, y float64) bool {
+	if len(p) == 0 || !p[0].contains(x, y) {
+		return false
+	}
+
+	for _, hole := range p[1:] {
+		if hole.contains(x, y) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// contains uses the even-odd rule to check if the point is inside the ring. A ring with less than 3 positions
+// has no area and contains no points.
+func (r Ring) contains(x, y float64) bool {
+	if len(r) < 3 {
+		return false
+	}
+
+	inside := false
+
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		xi, yi := r[i][0], r[i][1]
+		xj, yj := r[j][0], r[j][1]
+
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+type geofenceKey struct {
+	fence  int
+	icao24 string
+}
+
+type geofenceVisit struct {
+	entered  time.Time
+	lastSeen time.Time
+	dwelled  bool
+	state    StateVector
+}
+
+// GeofenceEngine keeps track of aircraft inside a set of geofences across state snapshots.
+// It is not safe for concurrent use.
+type GeofenceEngine struct {
+	Fences    []Geofence
+	DwellTime time.Duration // Time inside a geofence after which a dwell event is emitted. Zero disables dwell events.
+	Timeout   time.Duration // Time an aircraft inside a geofence can be missing from snapshots before an exit event is emitted.
+
+	visits map[geofenceKey]*geofenceVisit
+}
+
+// NewGeofenceEngine creates a new engine for the provided geofences
+func NewGeofenceEngine(fences []Geofence, dwellTime time.Duration) *GeofenceEngine {
+	return &GeofenceEngine{
+		Fences:    fences,
+		DwellTime: dwellTime,
+		visits:    make(map[geofenceKey]*geofenceVisit),
+	}
+}
+
+// Update evaluates a snapshot against the geofences and returns the resulting events.
+// Snapshots should be provided in chronological order.
+func (e *GeofenceEngine) Update(response StatesResponse) []GeofenceEvent {
+	if e.visits == nil {
+		e.visits = make(map[geofenceKey]*geofenceVisit)
+	}
+
+	now := time.Unix(response.Time, 0)
+	seen := make(map[geofenceKey]bool)
+	var events []GeofenceEvent
+
+	for _, state := range response.States {
+		if state.Latitude == nil || state.Longitude == nil {
+			// Without a position it is unknown if the aircraft is still inside, it is kept inside the geofences
+			// it was in since it is still in the snapshot
+			for i := range e.Fences {
+				key := geofenceKey{fence: i, icao24: state.ICAO24}
+
+				if visit, ok := e.visits[key]; ok {
+					seen[key] = true
+					visit.lastSeen = now
+				}
+			}
+
+			continue
+		}
+
+		for i, fence := range e.Fences {
+			key := geofenceKey{fence: i, icao24: state.ICAO24}
+			visit, wasInside := e.visits[key]
+
+			if !fence.Contains(state) {
+				if wasInside {
+					delete(e.visits, key)
+					events = append(events, e.event(GeofenceExit, key, now, now.Sub(visit.entered), state))
+				}
+
+				continue
+			}
+
+			seen[key] = true
+
+			if !wasInside {
+				e.visits[key] = &geofenceVisit{entered: now, lastSeen: now, state: state}
+				events = append(events, e.event(GeofenceEnter, key, now, 0, state))
+				continue
+			}
+
+			visit.lastSeen = now
+			visit.state = state
+
+			if e.DwellTime > 0 && !visit.dwelled && now.Sub(visit.entered) >= e.DwellTime {
+				visit.dwelled = true
+				events = append(events, e.event(GeofenceDwell, key, now, now.Sub(visit.entered), state))
+			}
+		}
+	}
+
+	var expired []geofenceKey
+
+	for key, visit := range e.visits {
+		if !seen[key] && now.Sub(visit.lastSeen) > e.Timeout {
+			expired = append(expired, key)
+		}
+	}
+
+	sort.Slice(expired, func(i, j int) bool {
+		if expired[i].fence != expired[j].fence {
+			return expired[i].fence < expired[j].fence
+		}
+
+		return expired[i].icao24 < expired[j].icao24
+	})
+
+	for _, key := range expired {
+		visit := e.visits[key]
+		delete(e.visits, key)
+		events = append(events, e.event(GeofenceExit, key, now, now.Sub(visit.entered), visit.state))
+	}
+
+	return events
+}
+
+// Inside returns the ICAO24 addresses of the aircraft currently inside the geofence with the provided id
+func (e *GeofenceEngine) Inside(fenceID string) []string {
+	var result []string
+
+	for key := range e.visits {
+		if e.Fences[key.fence].ID == fenceID {
+			result = append(result, key.icao24)
+		}
+	}
+
+	sort.Strings(result)
+
+	return result
+}
+
+func (e *GeofenceEngine) event(t GeofenceEventType, key geofenceKey, now time.Time, d time.Duration, s StateVector) GeofenceEvent {
+	return GeofenceEvent{
+		Type:     t,
+		FenceID:  e.Fences[key.fence].ID,
+		ICAO24:   key.icao24,
+		Time:     now,
+		Duration: d,
+		State:    s,
+	}
+}
@@ -0,0 +1,177 @@
+package goflight_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"testing"
+	"time"
+)
+
+const schipholGeoJSON = `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "properties": {"name": "schiphol", "maxAltitude": 1000},
+      "geometry": {
+        "type": "Polygon",
+        "coordinates": [[[4.70, 52.28], [4.80, 52.28], [4.80, 52.34], [4.70, 52.34], [4.70, 52.28]]]
+      }
+    }
+  ]
+}`
+
+func geofenceState(icao24 string, lat, lon, altitude float64) goflight.StateVector {
+	return goflight.StateVector{
+		ICAO24:       icao24,
+		Latitude:     floatPtr(lat),
+		Longitude:    floatPtr(lon),
+		BaroAltitude: floatPtr(altitude),
+	}
+}
+
+func TestParseGeofences(t *testing.T) {
+	fences, err := goflight.ParseGeofences([]byte(schipholGeoJSON))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(fences) != 1 {
+		t.Fatalf("expected 1 geofence, got %v", len(fences))
+	}
+
+	if fences[0].ID != "schiphol" {
+		t.Errorf("expected %v to equal schiphol", fences[0].ID)
+	}
+
+	if fences[0].MaxAltitude == nil || *fences[0].MaxAltitude != 1000 {
+		t.Error("expected max altitude to equal 1000")
+	}
+
+	_, err = goflight.ParseGeofences([]byte(`{"type": "Point", "coordinates": [4.7, 52.3]}`))
+
+	if !errors.Is(err, goflight.ErrInvalidGeoJSON) {
+		t.Errorf("expected error to be: %v", goflight.ErrInvalidGeoJSON.Error())
+	}
+}
+
+var geofenceContainsTests = []struct {
+	label    string
+	state    goflight.StateVector
+	expected bool
+}{
+	{"inside", geofenceState("484ac1", 52.31, 4.75, 300), true},
+	{"outside", geofenceState("484ac1", 52.40, 4.75, 300), false},
+	{"above altitude band", geofenceState("484ac1", 52.31, 4.75, 3000), false},
+	{"no position", goflight.StateVector{ICAO24: "484ac1"}, false},
+}
+
+func TestGeofence_Contains(t *testing.T) {
+	fences, err := goflight.ParseGeofences([]byte(schipholGeoJSON))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, tt := range geofenceContainsTests {
+		t.Run(tt.label, func(t *testing.T) {
+			if actual := fences[0].Contains(tt.state); actual != tt.expected {
+				t.Errorf("expected %v to equal %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+var degeneratePolygonTests = []struct {
+	label    string
+	polygon  goflight.Polygon
+	expected bool
+}{
+	{"nil polygon", nil, false},
+	{"no rings", goflight.Polygon{}, false},
+	{"empty ring", goflight.Polygon{goflight.Ring{}}, false},
+	{"two positions", goflight.Polygon{goflight.Ring{{4.7, 52.3}, {4.8, 52.3}}}, false},
+	{"degenerate hole", goflight.Polygon{
+		goflight.Ring{{4.7, 52.3}, {4.8, 52.3}, {4.8, 52.4}, {4.7, 52.3}},
+		goflight.Ring{{4.75, 52.35}},
+	}, true},
+}
+
+func TestGeofence_ContainsPosition_Degenerate(t *testing.T) {
+	for _, tt := range degeneratePolygonTests {
+		t.Run(tt.label, func(t *testing.T) {
+			fence := goflight.Geofence{ID: "degenerate", Polygons: []goflight.Polygon{tt.polygon}}
+
+			if contains := fence.ContainsPosition(52.35, 4.76); contains != tt.expected {
+				t.Errorf("expected %v to equal %v", contains, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGeofenceEngine_Update(t *testing.T) {
+	fences, err := goflight.ParseGeofences([]byte(schipholGeoJSON))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	engine := goflight.NewGeofenceEngine(fences, time.Minute)
+
+	snapshots := []goflight.StatesResponse{
+		{Time: 0, States: []goflight.StateVector{geofenceState("484ac1", 52.31, 4.75, 300)}},
+		{Time: 30, States: []goflight.StateVector{geofenceState("484ac1", 52.31, 4.76, 300), geofenceState("4846e1", 52.30, 4.75, 0)}},
+		{Time: 60, States: []goflight.StateVector{geofenceState("484ac1", 52.31, 4.77, 300), geofenceState("4846e1", 52.30, 4.75, 0)}},
+		{Time: 90, States: []goflight.StateVector{geofenceState("484ac1", 52.40, 4.77, 300)}},
+	}
+
+	expected := [][]goflight.GeofenceEventType{
+		{goflight.GeofenceEnter},
+		{goflight.GeofenceEnter},
+		{goflight.GeofenceDwell},
+		{goflight.GeofenceExit, goflight.GeofenceExit},
+	}
+
+	for i, snapshot := range snapshots {
+		events := engine.Update(snapshot)
+
+		if len(events) != len(expected[i]) {
+			t.Fatalf("snapshot %v: expected %v events, got %v", i, len(expected[i]), len(events))
+		}
+
+		for j, event := range events {
+			if event.Type != expected[i][j] {
+				t.Errorf("snapshot %v: expected %v to equal %v", i, event.Type, expected[i][j])
+			}
+		}
+	}
+
+	if inside := engine.Inside("schiphol"); len(inside) != 0 {
+		t.Errorf("expected no aircraft inside, got %v", inside)
+	}
+}
+
+func TestGeofenceEngine_Update_MissingPosition(t *testing.T) {
+	fences, err := goflight.ParseGeofences([]byte(schipholGeoJSON))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	engine := goflight.NewGeofenceEngine(fences, 0)
+	engine.Update(goflight.StatesResponse{Time: 0, States: []goflight.StateVector{geofenceState("484ac1", 52.31, 4.75, 300)}})
+
+	// The aircraft is still in the snapshot, so it stays inside while its position is unknown
+	if events := engine.Update(goflight.StatesResponse{Time: 30, States: []goflight.StateVector{{ICAO24: "484ac1"}}}); len(events) != 0 {
+		t.Errorf("expected no events without a position, got %+v", events)
+	}
+
+	if inside := engine.Inside("schiphol"); len(inside) != 1 || inside[0] != "484ac1" {
+		t.Errorf("expected 484ac1 to be inside, got %v", inside)
+	}
+
+	if events := engine.Update(goflight.StatesResponse{Time: 60}); len(events) != 1 || events[0].Type != goflight.GeofenceExit {
+		t.Errorf("expected an exit once the aircraft is missing, got %+v", events)
+	}
+}
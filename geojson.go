@@ -0,0 +1,225 @@
+package goflight
+
+import "math"
+
+// GeoJSONGeometry represents a RFC 7946 geometry object
+type GeoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// GeoJSONFeature represents a RFC 7946 feature object
+type GeoJSONFeature struct {
+	Type       string          `json:"type"`
+	ID         string          `json:"id,omitempty"`
+	Geometry   GeoJSONGeometry `json:"geometry"`
+	Properties interface{}     `json:"properties"`
+}
+
+// GeoJSONFeatureCollection represents a RFC 7946 feature collection object
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// TrackProperties are the properties of a track feature. Times holds the timestamp of every vertex in the LineString,
+// or of every vertex of all lines in order when the track is cut into a MultiLineString at the antimeridian.
+type TrackProperties struct {
+	ICAO24    string  `json:"icao24"`
+	CallSign  *string `json:"callsign"`
+	StartTime int64   `json:"start_time"`
+	EndTime   int64   `json:"end_time"`
+	Times     []int64 `json:"times"`
+}
+
+// NewGeoJSONFeatureCollection creates a feature collection from the provided features
+func NewGeoJSONFeatureCollection(features []GeoJSONFeature) GeoJSONFeatureCollection {
+	if features == nil {
+		// RFC 7946 requires the features member to be an array
+		features = []GeoJSONFeature{}
+	}
+
+	return GeoJSONFeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// GeoJSON returns a Point feature for the state vector. The properties are the keyed representation of the
+// state vector. ok is false when the state vector has no position.
+//
+// RFC 7946 defines the third coordinate as the height above the WGS-84 ellipsoid, so the geometric altitude is
+// used. The barometric altitude is only used when the geometric altitude is unknown, and the third coordinate is
+// omitted when both are unknown.
+func (s StateVector) GeoJSON() (feature GeoJSONFeature, ok bool) {
+	position, ok := s.Position()
+
+	if !ok {
+		return GeoJSONFeature{}, false
+	}
+
+	coordinates := []float64{position.Longitude, position.Latitude}
+
+	if s.GeoAltitude != nil {
+		coordinates = append(coordinates, *s.GeoAltitude)
+	} else if s.BaroAltitude != nil {
+		coordinates = append(coordinates, *s.BaroAltitude)
+	}
+
+	return GeoJSONFeature{
+		Type: "Feature",
+		ID:   s.ICAO24,
+		Geometry: GeoJSONGeometry{
+			Type:        "Point",
+			Coordinates: coordinates,
+		},
+		Properties: KeyedStateVector(s),
+	}, true
+}
+
+// GeoJSON returns a feature collection with a Point feature for every state vector with a position
+func (r StatesResponse) GeoJSON() GeoJSONFeatureCollection {
+	features := make([]GeoJSONFeature, 0, len(r.States))
+
+	for _, state := range r.States {
+		if feature, ok := state.GeoJSON(); ok {
+			features = append(features, feature)
+		}
+	}
+
+	return NewGeoJSONFeatureCollection(features)
+}
+
+// GeoJSON returns a LineString feature for the track. Waypoints without position are omitted. Waypoints have no
+// geometric altitude, so unlike for state vectors the barometric altitude is added as third coordinate, when it
+// is known for all waypoints. A track crossing the antimeridian is cut into a MultiLineString.
+// ok is false when less than two waypoints have a position.
+func (t Track) GeoJSON() (feature GeoJSONFeature, ok bool) {
+	coordinates := make([][]float64, 0, len(t.Path))
+	times := make([]int64, 0, len(t.Path))
+	withAltitude := true
+
+	for _, waypoint := range t.Path {
+		if waypoint.Latitude != nil && waypoint.Longitude != nil && waypoint.BaroAltitude == nil {
+			withAltitude = false
+		}
+	}
+
+	for _, waypoint := range t.Path {
+		position, ok := waypoint.Position()
+
+		if !ok {
+			continue
+		}
+
+		coordinate := []float64{position.Longitude, position.Latitude}
+
+		if withAltitude {
+			coordinate = append(coordinate, *waypoint.BaroAltitude)
+		}
+
+		coordinates = append(coordinates, coordinate)
+		times = append(times, waypoint.Time)
+	}
+
+	if len(coordinates) < 2 {
+		return GeoJSONFeature{}, false
+	}
+
+	geometry, times := lineStringGeometry(coordinates, times)
+
+	return GeoJSONFeature{
+		Type:     "Feature",
+		ID:       t.ICAO24,
+		Geometry: geometry,
+		Properties: TrackProperties{
+			ICAO24:    t.ICAO24,
+			CallSign:  t.CallSign,
+			StartTime: t.StartTime,
+			EndTime:   t.EndTime,
+			Times:     times,
+		},
+	}, true
+}
+
+// TracksGeoJSON returns a feature collection with a LineString feature for every track with at least two positions
+func TracksGeoJSON(tracks []Track) GeoJSONFeatureCollection {
+	features := make([]GeoJSONFeature, 0, len(tracks))
+
+	for _, track := range tracks {
+		if feature, ok := track.GeoJSON(); ok {
+			features = append(features, feature)
+		}
+	}
+
+	return NewGeoJSONFeatureCollection(features)
+}
+
+// GeoJSONBetween returns a LineString feature from the departure position to the arrival position of the flight,
+// e.g. the positions of its estimated departure and arrival airports. The properties are the flight. A line
+// crossing the antimeridian is cut into a MultiLineString.
+func (f Flight) GeoJSONBetween(departure, arrival Point) GeoJSONFeature {
+	geometry, _ := lineStringGeometry([][]float64{
+		{departure.Longitude, departure.Latitude},
+		{arrival.Longitude, arrival.Latitude},
+	}, nil)
+
+	return GeoJSONFeature{
+		Type:       "Feature",
+		ID:         f.ICAO24,
+		Geometry:   geometry,
+		Properties: f,
+	}
+}
+
+// lineStringGeometry returns a LineString of the coordinates, or a MultiLineString cut at the antimeridian as
+// RFC 7946 section 3.1.9 requires. A segment crosses the antimeridian when its longitudes differ more than 180
+// degrees, as the shorter way around the earth is assumed. times holds the time of every coordinate and can be
+// nil, the vertices added at the antimeridian get interpolated times.
+func lineStringGeometry(coordinates [][]float64, times []int64) (GeoJSONGeometry, []int64) {
+	lines := [][][]float64{{coordinates[0]}}
+	var vertexTimes []int64
+
+	if times != nil {
+		vertexTimes = []int64{times[0]}
+	}
+
+	for i := 1; i < len(coordinates); i++ {
+		a, b := coordinates[i-1], coordinates[i]
+
+		if d := b[0] - a[0]; math.Abs(d) > 180 {
+			// Longitude of the antimeridian on the side of a, and b's longitude continued past it
+			edge, unwrapped := 180.0, b[0]+360
+
+			if d > 0 {
+				edge, unwrapped = -180, b[0]-360
+			}
+
+			fraction := (edge - a[0]) / (unwrapped - a[0])
+			end := make([]float64, len(a))
+			end[0] = edge
+
+			for j := 1; j < len(a) && j < len(b); j++ {
+				end[j] = a[j] + fraction*(b[j]-a[j])
+			}
+
+			start := append([]float64{-edge}, end[1:]...)
+			lines[len(lines)-1] = append(lines[len(lines)-1], end)
+			lines = append(lines, [][]float64{start})
+
+			if times != nil {
+				t := times[i-1] + int64(math.Round(fraction*float64(times[i]-times[i-1])))
+				vertexTimes = append(vertexTimes, t, t)
+			}
+		}
+
+		lines[len(lines)-1] = append(lines[len(lines)-1], b)
+
+		if times != nil {
+			vertexTimes = append(vertexTimes, times[i])
+		}
+	}
+
+	if len(lines) == 1 {
+		return GeoJSONGeometry{Type: "LineString", Coordinates: lines[0]}, vertexTimes
+	}
+
+	return GeoJSONGeometry{Type: "MultiLineString", Coordinates: lines}, vertexTimes
+}
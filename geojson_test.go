@@ -0,0 +1,171 @@
+package goflight_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestStatesResponse_GeoJSON(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/states.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var response goflight.StatesResponse
+
+	if err = json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	response.States = append(response.States, goflight.StateVector{ICAO24: "c0ffee"})
+	encoded, err := json.Marshal(response.GeoJSON())
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var collection struct {
+		Type     string
+		Features []struct {
+			Type     string
+			ID       string
+			Geometry struct {
+				Type        string
+				Coordinates []float64
+			}
+			Properties map[string]interface{}
+		}
+	}
+
+	if err = json.Unmarshal(encoded, &collection); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if collection.Type != "FeatureCollection" {
+		t.Errorf("expected %v to equal FeatureCollection", collection.Type)
+	}
+
+	if len(collection.Features) != 6 {
+		t.Fatalf("expected the state without position to be omitted, got %v features", len(collection.Features))
+	}
+
+	feature := collection.Features[0]
+
+	// The geometric altitude is the third coordinate
+	if coordinates := feature.Geometry.Coordinates; feature.Geometry.Type != "Point" || len(coordinates) != 3 || coordinates[0] != 5.4945 || coordinates[1] != 51.8122 || coordinates[2] != 518.16 {
+		t.Errorf("unexpected geometry: %+v", feature.Geometry)
+	}
+
+	if coordinates := collection.Features[1].Geometry.Coordinates; len(coordinates) != 2 {
+		t.Errorf("expected the altitude to be omitted when it is unknown: %v", coordinates)
+	}
+
+	if feature.Properties["icao24"] != "484ac1" {
+		t.Errorf("expected %v to equal 484ac1", feature.Properties["icao24"])
+	}
+}
+
+func TestTrack_GeoJSON(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/track.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var track goflight.Track
+
+	if err = json.Unmarshal(data, &track); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	feature, ok := track.GeoJSON()
+
+	if !ok {
+		t.Fatal("expected track to have a feature")
+	}
+
+	coordinates := feature.Geometry.Coordinates.([][]float64)
+	properties := feature.Properties.(goflight.TrackProperties)
+
+	if feature.Geometry.Type != "LineString" || len(coordinates) != 5 {
+		t.Errorf("expected a LineString with 5 positions, got %v with %v", feature.Geometry.Type, len(coordinates))
+	}
+
+	if len(coordinates[0]) != 2 {
+		t.Error("expected altitude to be omitted when it is missing for a waypoint")
+	}
+
+	if len(properties.Times) != len(coordinates) || properties.Times[4] != 1586031309 {
+		t.Errorf("unexpected vertex times: %v", properties.Times)
+	}
+
+	track.Path = track.Path[4:5]
+
+	if _, ok = track.GeoJSON(); ok {
+		t.Error("expected track without positions to have no feature")
+	}
+
+	if collection := goflight.TracksGeoJSON(nil); collection.Features == nil {
+		t.Error("expected features to be an empty array")
+	}
+}
+
+func TestStateVector_GeoJSON_BaroAltitudeFallback(t *testing.T) {
+	state := goflight.StateVector{ICAO24: "484ac1", Latitude: floatPtr(52), Longitude: floatPtr(4), BaroAltitude: floatPtr(300)}
+	feature, ok := state.GeoJSON()
+
+	if coordinates := feature.Geometry.Coordinates.([]float64); !ok || len(coordinates) != 3 || coordinates[2] != 300 {
+		t.Errorf("expected the barometric altitude without geometric altitude: %+v", feature.Geometry)
+	}
+}
+
+func TestTrack_GeoJSON_Antimeridian(t *testing.T) {
+	track := goflight.Track{ICAO24: "c0ffee", Path: []goflight.Waypoint{
+		{Time: 100, Latitude: floatPtr(-17), Longitude: floatPtr(179), BaroAltitude: floatPtr(1000)},
+		{Time: 200, Latitude: floatPtr(-18), Longitude: floatPtr(-179), BaroAltitude: floatPtr(2000)},
+		{Time: 300, Latitude: floatPtr(-19), Longitude: floatPtr(-178), BaroAltitude: floatPtr(3000)},
+	}}
+	feature, ok := track.GeoJSON()
+
+	if !ok || feature.Geometry.Type != "MultiLineString" {
+		t.Fatalf("expected a MultiLineString, got %+v", feature.Geometry)
+	}
+
+	expected := [][][]float64{
+		{{179, -17, 1000}, {180, -17.5, 1500}},
+		{{-180, -17.5, 1500}, {-179, -18, 2000}, {-178, -19, 3000}},
+	}
+
+	if coordinates := feature.Geometry.Coordinates.([][][]float64); !reflect.DeepEqual(coordinates, expected) {
+		t.Errorf("expected %v to equal %v", coordinates, expected)
+	}
+
+	if times := feature.Properties.(goflight.TrackProperties).Times; !reflect.DeepEqual(times, []int64{100, 150, 150, 200, 300}) {
+		t.Errorf("expected a time for every vertex, got %v", times)
+	}
+}
+
+func TestFlight_GeoJSONBetween(t *testing.T) {
+	flight := goflight.Flight{ICAO24: "c0ffee", FirstSeen: 100}
+	feature := flight.GeoJSONBetween(goflight.Point{Latitude: 52.3086, Longitude: 4.7639}, goflight.Point{Latitude: 51.4706, Longitude: -0.4619})
+	expected := [][]float64{{4.7639, 52.3086}, {-0.4619, 51.4706}}
+
+	if coordinates, ok := feature.Geometry.Coordinates.([][]float64); !ok || feature.Geometry.Type != "LineString" || !reflect.DeepEqual(coordinates, expected) {
+		t.Errorf("unexpected geometry: %+v", feature.Geometry)
+	}
+
+	if properties := feature.Properties.(goflight.Flight); feature.ID != "c0ffee" || properties.FirstSeen != 100 {
+		t.Errorf("expected the flight as properties: %+v", feature)
+	}
+
+	// Auckland to Los Angeles crosses the antimeridian
+	feature = flight.GeoJSONBetween(goflight.Point{Latitude: -37, Longitude: 174.8}, goflight.Point{Latitude: 33.9, Longitude: -118.4})
+
+	if lines, ok := feature.Geometry.Coordinates.([][][]float64); !ok || feature.Geometry.Type != "MultiLineString" || len(lines) != 2 || lines[0][1][0] != 180 || lines[1][0][0] != -180 {
+		t.Errorf("expected the line to be cut at the antimeridian: %+v", feature.Geometry)
+	}
+}
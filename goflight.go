@@ -6,6 +6,9 @@ import (
 	"time"
 )
 
+//go:generate go run gen_accessors.go
+//go:generate go run gen_data.go
+
 const (
 	baseURL = "https://opensky-network.org"
 )
@@ -19,6 +22,7 @@ type Client struct {
 
 	States  *statesService
 	Flights *flightService
+	Tracks  *tracksService
 }
 
 // NewClient creates a new client with the provided credentials
@@ -41,6 +45,7 @@ func NewClient(username, password string, httpClient *http.Client) (*Client, err
 
 	c.States = &statesService{client: c}
 	c.Flights = &flightService{client: c}
+	c.Tracks = &tracksService{client: c}
 
 	return c, nil
 }
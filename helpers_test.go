@@ -33,3 +33,7 @@ func CreateTestHandler(status int, body []byte) http.HandlerFunc {
 
 	return handler
 }
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
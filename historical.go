@@ -0,0 +1,320 @@
+package goflight
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// historicalColumns are the columns of the Opensky historical state vector dumps
+var historicalColumns = []string{
+	"time", "icao24", "lat", "lon", "velocity", "heading", "vertrate", "callsign", "onground",
+	"alert", "spi", "squawk", "baroaltitude", "geoaltitude", "lastposupdate", "lastcontact",
+}
+
+type historicalRow struct {
+	time  int64
+	state StateVector
+}
+
+// HistoricalReader streams state vectors from an Opensky historical dataset dump, grouped into snapshots by their
+// time. The dump can be a plain CSV file, a gzip compressed CSV file or a tar archive of (compressed) CSV files.
+// Rows are expected to be ordered by time, like they are in the published dumps.
+type HistoricalReader struct {
+	closer  io.Closer
+	tar     *tar.Reader
+	csv     *csv.Reader
+	columns map[string]int
+	line    int
+	pending *historicalRow
+	done    bool
+	err     error
+}
+
+// OpenHistoricalDump opens a historical dump file. Close must be called when done reading.
+func OpenHistoricalDump(path string) (*HistoricalReader, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := NewHistoricalReader(f)
+
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	r.closer = f
+
+	return r, nil
+}
+
+// NewHistoricalReader creates a HistoricalReader. Gzip compression and tar archives are detected automatically.
+func NewHistoricalReader(r io.Reader) (*HistoricalReader, error) {
+	reader, err := decompress(r)
+
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewReader(reader)
+	header, _ := buffered.Peek(262)
+
+	if len(header) == 262 && bytes.Equal(header[257:262], []byte("ustar")) {
+		return &HistoricalReader{tar: tar.NewReader(buffered)}, nil
+	}
+
+	result := &HistoricalReader{}
+
+	if err = result.startCSV(buffered); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// decompress wraps the reader in a gzip reader when the content is gzip compressed
+func decompress(r io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(r)
+	header, _ := buffered.Peek(2)
+
+	if len(header) == 2 && header[0] == 0x1f && header[1] == 0x8b {
+		return gzip.NewReader(buffered)
+	}
+
+	return buffered, nil
+}
+
+func (r *HistoricalReader) startCSV(reader io.Reader) error {
+	r.csv = csv.NewReader(reader)
+	r.csv.FieldsPerRecord = -1
+	r.csv.ReuseRecord = true
+	r.line = 0
+
+	header, err := r.csv.Read()
+
+	if err != nil {
+		return err
+	}
+
+	r.line++
+	r.columns = make(map[string]int, len(header))
+
+	for i, name := range header {
+		r.columns[strings.TrimSpace(name)] = i
+	}
+
+	return nil
+}
+
+// nextFile starts reading the next CSV file from the tar archive
+func (r *HistoricalReader) nextFile() error {
+	if r.tar == nil {
+		return io.EOF
+	}
+
+	for {
+		header, err := r.tar.Next()
+
+		if err != nil {
+			return err
+		}
+
+		name := strings.TrimSuffix(header.Name, ".gz")
+
+		if header.Typeflag != tar.TypeReg || !strings.HasSuffix(name, ".csv") {
+			continue
+		}
+
+		reader, err := decompress(r.tar)
+
+		if err != nil {
+			return err
+		}
+
+		if err = r.startCSV(reader); err == io.EOF {
+			// Empty file without header
+			continue
+		}
+
+		return err
+	}
+}
+
+func (r *HistoricalReader) readRow() (*historicalRow, error) {
+	for {
+		if r.csv == nil {
+			if err := r.nextFile(); err != nil {
+				return nil, err
+			}
+		}
+
+		record, err := r.csv.Read()
+
+		if err == io.EOF {
+			r.csv = nil
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		r.line++
+
+		return r.parseRow(record)
+	}
+}
+
+func (r *HistoricalReader) parseRow(record []string) (*historicalRow, error) {
+	var row historicalRow
+	s := &row.state
+
+	for _, name := range historicalColumns {
+		index, ok := r.columns[name]
+
+		if !ok || index >= len(record) {
+			continue
+		}
+
+		value := record[index]
+		var err error
+
+		switch name {
+		case "time":
+			row.time, err = parseHistoricalTime(value)
+		case "icao24":
+			s.ICAO24 = value
+			// The dumps have no origin country column, so it is inferred from the address
+			s.OriginCountry, _ = ICAO24Country(value)
+		case "lat":
+			s.Latitude, err = parseCSVFloat(value)
+		case "lon":
+			s.Longitude, err = parseCSVFloat(value)
+		case "velocity":
+			s.Velocity, err = parseCSVFloat(value)
+		case "heading":
+			s.TrueTrack, err = parseCSVFloat(value)
+		case "vertrate":
+			s.VerticalRate, err = parseCSVFloat(value)
+		case "callsign":
+			s.Callsign = parseCSVString(value)
+		case "onground":
+			if value != "" {
+				s.OnGround, err = strconv.ParseBool(value)
+			}
+		case "spi":
+			s.Spi, err = parseCSVBool(value)
+		case "squawk":
+			s.Squawk = parseCSVString(value)
+		case "baroaltitude":
+			s.BaroAltitude, err = parseCSVFloat(value)
+		case "geoaltitude":
+			s.GeoAltitude, err = parseCSVFloat(value)
+		case "lastposupdate":
+			if value != "" {
+				var t int64
+				t, err = parseHistoricalTime(value)
+				s.TimePosition = &t
+			}
+		case "lastcontact":
+			if value != "" {
+				s.LastContact, err = parseHistoricalTime(value)
+			}
+		}
+
+		if err != nil {
+			return nil, &CSVParseError{Line: r.line, Column: name, Err: err}
+		}
+	}
+
+	return &row, nil
+}
+
+// parseHistoricalTime parses a unix timestamp, which has a fractional part for some columns in the dumps
+func parseHistoricalTime(value string) (int64, error) {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i, nil
+	}
+
+	f, err := strconv.ParseFloat(value, 64)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(math.Floor(f)), nil
+}
+
+// Read returns the state vectors of the next snapshot. It returns io.EOF when there are no more snapshots.
+// When a row can't be read, the states read so far of the snapshot are returned with the error, so the snapshot
+// is incomplete. The reader stops at the first error and returns it from every later call.
+func (r *HistoricalReader) Read() (StatesResponse, error) {
+	if r.err != nil {
+		return StatesResponse{}, r.err
+	}
+
+	if r.done {
+		return StatesResponse{}, io.EOF
+	}
+
+	first := r.pending
+	r.pending = nil
+
+	if first == nil {
+		row, err := r.readRow()
+
+		if err != nil {
+			if err == io.EOF {
+				r.done = true
+			} else {
+				r.err = err
+			}
+
+			return StatesResponse{}, err
+		}
+
+		first = row
+	}
+
+	snapshot := StatesResponse{Time: first.time, States: []StateVector{first.state}}
+
+	for {
+		row, err := r.readRow()
+
+		if err == io.EOF {
+			r.done = true
+			return snapshot, nil
+		}
+
+		if err != nil {
+			r.err = err
+			return snapshot, err
+		}
+
+		if row.time != snapshot.Time {
+			r.pending = row
+			return snapshot, nil
+		}
+
+		snapshot.States = append(snapshot.States, row.state)
+	}
+}
+
+// Close closes the underlying file when the reader was created by OpenHistoricalDump
+func (r *HistoricalReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+
+	return r.closer.Close()
+}
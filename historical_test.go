@@ -0,0 +1,177 @@
+package goflight_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"github.com/marcelblijleven/goflight"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := gz.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return buf.Bytes()
+}
+
+func tarBytes(t *testing.T, files map[string][]byte, names ...string) []byte {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	for _, name := range names {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}
+
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if _, err := tw.Write(files[name]); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return buf.Bytes()
+}
+
+func readHistoricalSnapshots(t *testing.T, r *goflight.HistoricalReader) []goflight.StatesResponse {
+	var snapshots []goflight.StatesResponse
+
+	for {
+		snapshot, err := r.Read()
+
+		if err == io.EOF {
+			return snapshots
+		}
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+}
+
+func TestHistoricalReader_Read(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/historical_states.csv")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	inputs := map[string][]byte{
+		"csv":     data,
+		"csv.gz":  gzipBytes(t, data),
+		"tar":     tarBytes(t, map[string][]byte{"README": []byte("readme"), "states.csv.gz": gzipBytes(t, data)}, "README", "states.csv.gz"),
+		"tar.gz":  gzipBytes(t, tarBytes(t, map[string][]byte{"states.csv": data}, "states.csv")),
+		"two csv": tarBytes(t, map[string][]byte{"00.csv": data, "01.csv.gz": gzipBytes(t, data)}, "00.csv", "01.csv.gz"),
+	}
+
+	for label, input := range inputs {
+		t.Run(label, func(t *testing.T) {
+			r, err := goflight.NewHistoricalReader(bytes.NewReader(input))
+
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			snapshots := readHistoricalSnapshots(t, r)
+			expected := 2
+
+			if label == "two csv" {
+				expected = 4
+			}
+
+			if len(snapshots) != expected {
+				t.Fatalf("expected %v snapshots, got %v", expected, len(snapshots))
+			}
+
+			first, second := snapshots[0], snapshots[1]
+
+			if first.Time != 1586031300 || len(first.States) != 2 || second.Time != 1586031310 || len(second.States) != 2 {
+				t.Errorf("unexpected snapshots: %+v", snapshots)
+			}
+
+			state := first.States[0]
+
+			if state.ICAO24 != "484ac1" || *state.Callsign != "ZXP25   " || *state.TrueTrack != 328.39 || *state.TimePosition != 1586031299 || state.LastContact != 1586031299 {
+				t.Errorf("unexpected state vector: %+v", state)
+			}
+
+			if state.OriginCountry != "Kingdom of the Netherlands" {
+				t.Errorf("expected origin country %q to be inferred from the address", state.OriginCountry)
+			}
+
+			if !first.States[1].OnGround || first.States[1].BaroAltitude != nil || first.States[1].Squawk != nil {
+				t.Errorf("unexpected state vector: %+v", first.States[1])
+			}
+
+			if second.States[1].Latitude != nil || second.States[1].TimePosition != nil {
+				t.Errorf("expected empty cells to be nil: %+v", second.States[1])
+			}
+		})
+	}
+}
+
+func TestHistoricalReader_Read_Malformed(t *testing.T) {
+	data := `time,icao24,lat,lon
+1586031300,484ac1,51.8122,5.4945
+1586031300,4846e1,not a latitude,4.7535
+1586031300,06a2e1,42.564,-3.0762
+1586031310,484ac1,51.8141,5.4932
+`
+	r, err := goflight.NewHistoricalReader(bytes.NewReader([]byte(data)))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	snapshot, err := r.Read()
+	parseErr, ok := err.(*goflight.CSVParseError)
+
+	if !ok || parseErr.Line != 3 || parseErr.Column != "lat" {
+		t.Fatalf("expected a parse error for lat on line 3, got %v", err)
+	}
+
+	// The states read before the malformed row are returned with the error
+	if snapshot.Time != 1586031300 || len(snapshot.States) != 1 || snapshot.States[0].ICAO24 != "484ac1" {
+		t.Errorf("unexpected partial snapshot: %+v", snapshot)
+	}
+
+	// Reading stops, so the rest of the snapshot isn't returned as a separate snapshot
+	if snapshot, err = r.Read(); err != parseErr || len(snapshot.States) != 0 {
+		t.Errorf("expected the reader to keep returning %v, got %v and %+v", parseErr, err, snapshot)
+	}
+}
+
+func TestOpenHistoricalDump(t *testing.T) {
+	r, err := goflight.OpenHistoricalDump("./mocks/historical_states.csv")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defer r.Close()
+
+	if snapshots := readHistoricalSnapshots(t, r); len(snapshots) != 2 {
+		t.Errorf("expected 2 snapshots, got %v", len(snapshots))
+	}
+
+	if _, err = goflight.OpenHistoricalDump("./mocks/missing.csv"); err == nil {
+		t.Error("expected err to be non nil")
+	}
+}
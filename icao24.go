@@ -0,0 +1,317 @@
+package goflight
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// addressBlock is a block of ICAO 24-bit addresses
+type addressBlock struct {
+	start, end uint32
+	country    string
+}
+
+// countryBlocks are the address blocks allocated to states by ICAO Annex 10 Volume III. Country names follow the
+// names used by the Opensky API where they differ. Some blocks lie within a larger block, the smallest block
+// containing an address determines its country.
+var countryBlocks = []addressBlock{
+	{0x004000, 0x0043ff, "Zimbabwe"},
+	{0x006000, 0x006fff, "Mozambique"},
+	{0x008000, 0x00ffff, "South Africa"},
+	{0x010000, 0x017fff, "Egypt"},
+	{0x018000, 0x01ffff, "Libya"},
+	{0x020000, 0x027fff, "Morocco"},
+	{0x028000, 0x02ffff, "Tunisia"},
+	{0x030000, 0x0303ff, "Botswana"},
+	{0x032000, 0x032fff, "Burundi"},
+	{0x034000, 0x034fff, "Cameroon"},
+	{0x035000, 0x0353ff, "Comoros"},
+	{0x036000, 0x036fff, "Congo"},
+	{0x038000, 0x038fff, "Côte d'Ivoire"},
+	{0x03e000, 0x03efff, "Gabon"},
+	{0x040000, 0x040fff, "Ethiopia"},
+	{0x042000, 0x042fff, "Equatorial Guinea"},
+	{0x044000, 0x044fff, "Ghana"},
+	{0x046000, 0x046fff, "Guinea"},
+	{0x048000, 0x0483ff, "Guinea-Bissau"},
+	{0x04a000, 0x04a3ff, "Lesotho"},
+	{0x04c000, 0x04cfff, "Kenya"},
+	{0x050000, 0x050fff, "Liberia"},
+	{0x054000, 0x054fff, "Madagascar"},
+	{0x058000, 0x058fff, "Malawi"},
+	{0x05a000, 0x05a3ff, "Maldives"},
+	{0x05c000, 0x05cfff, "Mali"},
+	{0x05e000, 0x05e3ff, "Mauritania"},
+	{0x060000, 0x0603ff, "Mauritius"},
+	{0x062000, 0x062fff, "Niger"},
+	{0x064000, 0x064fff, "Nigeria"},
+	{0x068000, 0x068fff, "Uganda"},
+	{0x06a000, 0x06a3ff, "Qatar"},
+	{0x06c000, 0x06cfff, "Central African Republic"},
+	{0x06e000, 0x06efff, "Rwanda"},
+	{0x070000, 0x070fff, "Senegal"},
+	{0x074000, 0x0743ff, "Seychelles"},
+	{0x076000, 0x0763ff, "Sierra Leone"},
+	{0x078000, 0x078fff, "Somalia"},
+	{0x07a000, 0x07a3ff, "Eswatini"},
+	{0x07c000, 0x07cfff, "Sudan"},
+	{0x080000, 0x080fff, "United Republic of Tanzania"},
+	{0x084000, 0x084fff, "Chad"},
+	{0x088000, 0x088fff, "Togo"},
+	{0x08a000, 0x08afff, "Zambia"},
+	{0x08c000, 0x08cfff, "Democratic Republic of the Congo"},
+	{0x090000, 0x090fff, "Angola"},
+	{0x094000, 0x0943ff, "Benin"},
+	{0x096000, 0x0963ff, "Cabo Verde"},
+	{0x098000, 0x0983ff, "Djibouti"},
+	{0x09a000, 0x09afff, "Gambia"},
+	{0x09c000, 0x09cfff, "Burkina Faso"},
+	{0x09e000, 0x09e3ff, "Sao Tome and Principe"},
+	{0x0a0000, 0x0a7fff, "Algeria"},
+	{0x0a8000, 0x0a8fff, "Bahamas"},
+	{0x0aa000, 0x0aa3ff, "Barbados"},
+	{0x0ab000, 0x0ab3ff, "Belize"},
+	{0x0ac000, 0x0acfff, "Colombia"},
+	{0x0ae000, 0x0aefff, "Costa Rica"},
+	{0x0b0000, 0x0b0fff, "Cuba"},
+	{0x0b2000, 0x0b2fff, "El Salvador"},
+	{0x0b4000, 0x0b4fff, "Guatemala"},
+	{0x0b6000, 0x0b6fff, "Guyana"},
+	{0x0b8000, 0x0b8fff, "Haiti"},
+	{0x0ba000, 0x0bafff, "Honduras"},
+	{0x0bc000, 0x0bc3ff, "Saint Vincent and the Grenadines"},
+	{0x0be000, 0x0befff, "Jamaica"},
+	{0x0c0000, 0x0c0fff, "Nicaragua"},
+	{0x0c2000, 0x0c2fff, "Panama"},
+	{0x0c4000, 0x0c4fff, "Dominican Republic"},
+	{0x0c6000, 0x0c6fff, "Trinidad and Tobago"},
+	{0x0c8000, 0x0c8fff, "Suriname"},
+	{0x0ca000, 0x0ca3ff, "Antigua and Barbuda"},
+	{0x0cc000, 0x0cc3ff, "Grenada"},
+	{0x0d0000, 0x0d7fff, "Mexico"},
+	{0x0d8000, 0x0dffff, "Venezuela"},
+	{0x100000, 0x1fffff, "Russian Federation"},
+	{0x201000, 0x2013ff, "Namibia"},
+	{0x202000, 0x2023ff, "Eritrea"},
+	{0x300000, 0x33ffff, "Italy"},
+	{0x340000, 0x37ffff, "Spain"},
+	{0x380000, 0x3bffff, "France"},
+	{0x3c0000, 0x3fffff, "Germany"},
+	{0x400000, 0x43ffff, "United Kingdom"},
+	{0x440000, 0x447fff, "Austria"},
+	{0x448000, 0x44ffff, "Belgium"},
+	{0x450000, 0x457fff, "Bulgaria"},
+	{0x458000, 0x45ffff, "Denmark"},
+	{0x460000, 0x467fff, "Finland"},
+	{0x468000, 0x46ffff, "Greece"},
+	{0x470000, 0x477fff, "Hungary"},
+	{0x478000, 0x47ffff, "Norway"},
+	{0x480000, 0x487fff, "Kingdom of the Netherlands"},
+	{0x488000, 0x48ffff, "Poland"},
+	{0x490000, 0x497fff, "Portugal"},
+	{0x498000, 0x49ffff, "Czech Republic"},
+	{0x4a0000, 0x4a7fff, "Romania"},
+	{0x4a8000, 0x4affff, "Sweden"},
+	{0x4b0000, 0x4b7fff, "Switzerland"},
+	{0x4b8000, 0x4bffff, "Turkey"},
+	{0x4c0000, 0x4c7fff, "Serbia"},
+	{0x4c8000, 0x4c83ff, "Cyprus"},
+	{0x4ca000, 0x4cafff, "Ireland"},
+	{0x4cc000, 0x4ccfff, "Iceland"},
+	{0x4d0000, 0x4d03ff, "Luxembourg"},
+	{0x4d2000, 0x4d23ff, "Malta"},
+	{0x4d4000, 0x4d43ff, "Monaco"},
+	{0x500000, 0x5003ff, "San Marino"},
+	{0x501000, 0x5013ff, "Albania"},
+	{0x501c00, 0x501fff, "Croatia"},
+	{0x502c00, 0x502fff, "Latvia"},
+	{0x503c00, 0x503fff, "Lithuania"},
+	{0x504c00, 0x504fff, "Republic of Moldova"},
+	{0x505c00, 0x505fff, "Slovakia"},
+	{0x506c00, 0x506fff, "Slovenia"},
+	{0x507c00, 0x507fff, "Uzbekistan"},
+	{0x508000, 0x50ffff, "Ukraine"},
+	{0x510000, 0x5103ff, "Belarus"},
+	{0x511000, 0x5113ff, "Estonia"},
+	{0x512000, 0x5123ff, "North Macedonia"},
+	{0x513000, 0x5133ff, "Bosnia and Herzegovina"},
+	{0x514000, 0x5143ff, "Georgia"},
+	{0x515000, 0x5153ff, "Tajikistan"},
+	{0x516000, 0x5163ff, "Montenegro"},
+	{0x600000, 0x6003ff, "Armenia"},
+	{0x600800, 0x600bff, "Azerbaijan"},
+	{0x601000, 0x6013ff, "Kyrgyzstan"},
+	{0x601800, 0x601bff, "Turkmenistan"},
+	{0x680000, 0x6803ff, "Bhutan"},
+	{0x681000, 0x6813ff, "Micronesia, Federated States of"},
+	{0x682000, 0x6823ff, "Mongolia"},
+	{0x683000, 0x6833ff, "Kazakhstan"},
+	{0x684000, 0x6843ff, "Palau"},
+	{0x700000, 0x700fff, "Afghanistan"},
+	{0x702000, 0x702fff, "Bangladesh"},
+	{0x704000, 0x704fff, "Myanmar"},
+	{0x706000, 0x706fff, "Kuwait"},
+	{0x708000, 0x708fff, "Lao People's Democratic Republic"},
+	{0x70a000, 0x70afff, "Nepal"},
+	{0x70c000, 0x70c3ff, "Oman"},
+	{0x70e000, 0x70efff, "Cambodia"},
+	{0x710000, 0x717fff, "Saudi Arabia"},
+	{0x718000, 0x71ffff, "Republic of Korea"},
+	{0x720000, 0x727fff, "Democratic People's Republic of Korea"},
+	{0x728000, 0x72ffff, "Iraq"},
+	{0x730000, 0x737fff, "Iran, Islamic Republic of"},
+	{0x738000, 0x73ffff, "Israel"},
+	{0x740000, 0x747fff, "Jordan"},
+	{0x748000, 0x74ffff, "Lebanon"},
+	{0x750000, 0x757fff, "Malaysia"},
+	{0x758000, 0x75ffff, "Philippines"},
+	{0x760000, 0x767fff, "Pakistan"},
+	{0x768000, 0x76ffff, "Singapore"},
+	{0x770000, 0x777fff, "Sri Lanka"},
+	{0x778000, 0x77ffff, "Syrian Arab Republic"},
+	{0x780000, 0x7bffff, "China"},
+	{0x789000, 0x789fff, "Hong Kong"},
+	{0x7c0000, 0x7fffff, "Australia"},
+	{0x800000, 0x83ffff, "India"},
+	{0x840000, 0x87ffff, "Japan"},
+	{0x880000, 0x887fff, "Thailand"},
+	{0x888000, 0x88ffff, "Viet Nam"},
+	{0x890000, 0x890fff, "Yemen"},
+	{0x894000, 0x894fff, "Bahrain"},
+	{0x895000, 0x8953ff, "Brunei Darussalam"},
+	{0x896000, 0x896fff, "United Arab Emirates"},
+	{0x897000, 0x8973ff, "Solomon Islands"},
+	{0x898000, 0x898fff, "Papua New Guinea"},
+	{0x899000, 0x8993ff, "Taiwan"},
+	{0x8a0000, 0x8a7fff, "Indonesia"},
+	{0x900000, 0x9003ff, "Marshall Islands"},
+	{0x901000, 0x9013ff, "Cook Islands"},
+	{0x902000, 0x9023ff, "Samoa"},
+	{0xa00000, 0xafffff, "United States"},
+	{0xc00000, 0xc3ffff, "Canada"},
+	{0xc80000, 0xc87fff, "New Zealand"},
+	{0xc88000, 0xc88fff, "Fiji"},
+	{0xc8a000, 0xc8a3ff, "Nauru"},
+	{0xc8c000, 0xc8c3ff, "Saint Lucia"},
+	{0xc8d000, 0xc8d3ff, "Tonga"},
+	{0xc8e000, 0xc8e3ff, "Kiribati"},
+	{0xc90000, 0xc903ff, "Vanuatu"},
+	{0xe00000, 0xe3ffff, "Argentina"},
+	{0xe40000, 0xe7ffff, "Brazil"},
+	{0xe80000, 0xe80fff, "Chile"},
+	{0xe84000, 0xe84fff, "Ecuador"},
+	{0xe88000, 0xe88fff, "Paraguay"},
+	{0xe8c000, 0xe8cfff, "Peru"},
+	{0xe90000, 0xe90fff, "Uruguay"},
+	{0xe94000, 0xe94fff, "Bolivia"},
+}
+
+// militaryBlocks are address ranges known to be used by military aircraft, they are not part of the ICAO
+// allocation and are incomplete
+var militaryBlocks = []addressBlock{
+	{0x010070, 0x01008f, "Egypt"},
+	{0x0a4000, 0x0a4fff, "Algeria"},
+	{0x33ff00, 0x33ffff, "Italy"},
+	{0x350000, 0x37ffff, "Spain"},
+	{0x3aa000, 0x3affff, "France"},
+	{0x3b7000, 0x3bffff, "France"},
+	{0x3ea000, 0x3ebfff, "Germany"},
+	{0x3f4000, 0x3fbfff, "Germany"},
+	{0x400000, 0x40003f, "United Kingdom"},
+	{0x43c000, 0x43cfff, "United Kingdom"},
+	{0x444000, 0x446fff, "Austria"},
+	{0x44f000, 0x44ffff, "Belgium"},
+	{0x457000, 0x457fff, "Bulgaria"},
+	{0x45f400, 0x45f4ff, "Denmark"},
+	{0x468000, 0x4683ff, "Greece"},
+	{0x473c00, 0x473c0f, "Hungary"},
+	{0x478100, 0x4781ff, "Norway"},
+	{0x480000, 0x480fff, "Kingdom of the Netherlands"},
+	{0x48d800, 0x48d87f, "Poland"},
+	{0x497c00, 0x497cff, "Portugal"},
+	{0x498420, 0x49842f, "Czech Republic"},
+	{0x4b7000, 0x4b7fff, "Switzerland"},
+	{0x4b8200, 0x4b82ff, "Turkey"},
+	{0x70c070, 0x70c07f, "Oman"},
+	{0x710258, 0x71028f, "Saudi Arabia"},
+	{0x710380, 0x71039f, "Saudi Arabia"},
+	{0x738a00, 0x738aff, "Israel"},
+	{0x7cf800, 0x7cfaff, "Australia"},
+	{0x800200, 0x8002ff, "India"},
+	{0xadf7c8, 0xafffff, "United States"},
+	{0xc20000, 0xc3ffff, "Canada"},
+	{0xc87f00, 0xc87fff, "New Zealand"},
+	{0xe40000, 0xe41fff, "Brazil"},
+}
+
+// parseICAO24 parses a hexadecimal ICAO 24-bit address
+func parseICAO24(icao24 string) (uint32, bool) {
+	icao24 = strings.TrimSpace(icao24)
+
+	if !isHex(icao24, 6) {
+		return 0, false
+	}
+
+	address, err := strconv.ParseUint(icao24, 16, 32)
+
+	return uint32(address), err == nil
+}
+
+// findBlock returns the smallest block containing the address
+func findBlock(blocks []addressBlock, address uint32) (addressBlock, bool) {
+	var result addressBlock
+	found := false
+
+	for _, b := range blocks {
+		if address >= b.start && address <= b.end && (!found || b.end-b.start < result.end-result.start) {
+			result, found = b, true
+		}
+	}
+
+	return result, found
+}
+
+// ICAO24Country returns the country the ICAO 24-bit address is allocated to, ok is false when the address is
+// invalid or not allocated to a country
+func ICAO24Country(icao24 string) (country string, ok bool) {
+	address, ok := parseICAO24(icao24)
+
+	if !ok {
+		return "", false
+	}
+
+	block, ok := findBlock(countryBlocks, address)
+
+	return block.country, ok
+}
+
+// IsMilitaryICAO24 reports whether the ICAO 24-bit address is in a range known to be used by military aircraft
+func IsMilitaryICAO24(icao24 string) bool {
+	address, ok := parseICAO24(icao24)
+
+	if !ok {
+		return false
+	}
+
+	_, ok = findBlock(militaryBlocks, address)
+
+	return ok
+}
+
+// Countries returns the names of all countries with an allocated address block, in alphabetical order
+func Countries() []string {
+	seen := make(map[string]bool)
+	var countries []string
+
+	for _, b := range countryBlocks {
+		if !seen[b.country] {
+			seen[b.country] = true
+			countries = append(countries, b.country)
+		}
+	}
+
+	sort.Strings(countries)
+
+	return countries
+}
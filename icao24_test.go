@@ -0,0 +1,91 @@
+package goflight_test
+
+import (
+	"github.com/marcelblijleven/goflight"
+	"sort"
+	"testing"
+)
+
+var icao24CountryTests = []struct {
+	icao24  string
+	country string
+	ok      bool
+}{
+	{"484ac1", "Kingdom of the Netherlands", true},
+	{"4846E1", "Kingdom of the Netherlands", true},
+	{"06a2e1", "Qatar", true},
+	{"c05ed0", "Canada", true},
+	{"406d21", "United Kingdom", true},
+	{"a2e5ec", "United States", true},
+	{"3c6444", "Germany", true},
+	{"780a3b", "China", true},
+	{"789123", "Hong Kong", true},
+	{"000001", "", false},
+	{"f00000", "", false},
+	{"", "", false},
+	{"484ac", "", false},
+	{"484ac1a", "", false},
+	{"zzzzzz", "", false},
+}
+
+func TestICAO24Country(t *testing.T) {
+	for _, tt := range icao24CountryTests {
+		t.Run(tt.icao24, func(t *testing.T) {
+			country, ok := goflight.ICAO24Country(tt.icao24)
+
+			if country != tt.country || ok != tt.ok {
+				t.Errorf("expected (%q, %v) to equal (%q, %v)", country, ok, tt.country, tt.ok)
+			}
+		})
+	}
+}
+
+func TestICAO24Country_MatchesMocks(t *testing.T) {
+	for _, s := range readMockStates(t) {
+		if country, _ := goflight.ICAO24Country(s.ICAO24); country != s.OriginCountry {
+			t.Errorf("expected %q for %v to equal %q", country, s.ICAO24, s.OriginCountry)
+		}
+	}
+}
+
+var militaryICAO24Tests = []struct {
+	icao24   string
+	military bool
+}{
+	{"ae1460", true},
+	{"AE1460", true},
+	{"a2e5ec", false},
+	{"480123", true},
+	{"484ac1", false},
+	{"43c5e1", true},
+	{"406d21", false},
+	{"3ea123", true},
+	{"c2ab12", true},
+	{"c05ed0", false},
+	{"", false},
+	{"xyz", false},
+}
+
+func TestIsMilitaryICAO24(t *testing.T) {
+	for _, tt := range militaryICAO24Tests {
+		t.Run(tt.icao24, func(t *testing.T) {
+			if military := goflight.IsMilitaryICAO24(tt.icao24); military != tt.military {
+				t.Errorf("expected %v to equal %v", military, tt.military)
+			}
+		})
+	}
+}
+
+func TestCountries(t *testing.T) {
+	countries := goflight.Countries()
+
+	if !sort.StringsAreSorted(countries) {
+		t.Errorf("expected countries to be sorted")
+	}
+
+	for i := 1; i < len(countries); i++ {
+		if countries[i] == countries[i-1] {
+			t.Errorf("expected %q to be listed once", countries[i])
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package goflight
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// StatesEncoding determines how state vectors are encoded when marshalling a StatesResponse
+type StatesEncoding int
+
+const (
+	// PositionalEncoding encodes state vectors as arrays, like the Opensky API does
+	PositionalEncoding StatesEncoding = iota
+	// KeyedEncoding encodes state vectors as objects with snake_case field names
+	KeyedEncoding
+)
+
+// KeyedStateVector is a StateVector which is marshalled as an object with named fields instead of the
+// positional array. Timestamps are included both as unix seconds and in RFC 3339 format.
+type KeyedStateVector StateVector
+
+type keyedStateVectorJSON struct {
+	ICAO24              string     `json:"icao24"`
+	Callsign            *string    `json:"callsign"`
+	OriginCountry       string     `json:"origin_country"`
+	TimePosition        *int64     `json:"time_position"`
+	TimePositionRFC3339 *time.Time `json:"time_position_rfc3339"`
+	LastContact         int64      `json:"last_contact"`
+	LastContactRFC3339  time.Time  `json:"last_contact_rfc3339"`
+	Longitude           *float64   `json:"longitude"`
+	Latitude            *float64   `json:"latitude"`
+	BaroAltitude        *float64   `json:"baro_altitude"`
+	OnGround            bool       `json:"on_ground"`
+	Velocity            *float64   `json:"velocity"`
+	TrueTrack           *float64   `json:"true_track"`
+	VerticalRate        *float64   `json:"vertical_rate"`
+	Sensors             *[]int     `json:"sensors"`
+	GeoAltitude         *float64   `json:"geo_altitude"`
+	Squawk              *string    `json:"squawk"`
+	Spi                 *bool      `json:"spi"`
+	PositionSource      int        `json:"position_source"`
+	Category            *int       `json:"category,omitempty"`
+}
+
+// MarshalJSON marshals the state vector into an object with snake_case field names
+func (k KeyedStateVector) MarshalJSON() ([]byte, error) {
+	tmp := keyedStateVectorJSON{
+		ICAO24:             k.ICAO24,
+		Callsign:           k.Callsign,
+		OriginCountry:      k.OriginCountry,
+		TimePosition:       k.TimePosition,
+		LastContact:        k.LastContact,
+		LastContactRFC3339: time.Unix(k.LastContact, 0).UTC(),
+		Longitude:          k.Longitude,
+		Latitude:           k.Latitude,
+		BaroAltitude:       k.BaroAltitude,
+		OnGround:           k.OnGround,
+		Velocity:           k.Velocity,
+		TrueTrack:          k.TrueTrack,
+		VerticalRate:       k.VerticalRate,
+		Sensors:            k.Sensors,
+		GeoAltitude:        k.GeoAltitude,
+		Squawk:             k.Squawk,
+		Spi:                k.Spi,
+		PositionSource:     k.PositionSource,
+		Category:           k.Category,
+	}
+
+	if k.TimePosition != nil {
+		timePosition := time.Unix(*k.TimePosition, 0).UTC()
+		tmp.TimePositionRFC3339 = &timePosition
+	}
+
+	return json.Marshal(tmp)
+}
+
+// UnmarshalJSON unmarshals an object with snake_case field names onto the state vector.
+// The unix timestamps take precedence over the RFC 3339 timestamps.
+func (k *KeyedStateVector) UnmarshalJSON(buf []byte) error {
+	var tmp keyedStateVectorJSON
+
+	if err := json.Unmarshal(buf, &tmp); err != nil {
+		return err
+	}
+
+	if tmp.TimePosition == nil && tmp.TimePositionRFC3339 != nil {
+		timePosition := tmp.TimePositionRFC3339.Unix()
+		tmp.TimePosition = &timePosition
+	}
+
+	if tmp.LastContact == 0 && !tmp.LastContactRFC3339.IsZero() {
+		tmp.LastContact = tmp.LastContactRFC3339.Unix()
+	}
+
+	*k = KeyedStateVector{
+		ICAO24:         tmp.ICAO24,
+		Callsign:       tmp.Callsign,
+		OriginCountry:  tmp.OriginCountry,
+		TimePosition:   tmp.TimePosition,
+		LastContact:    tmp.LastContact,
+		Longitude:      tmp.Longitude,
+		Latitude:       tmp.Latitude,
+		BaroAltitude:   tmp.BaroAltitude,
+		OnGround:       tmp.OnGround,
+		Velocity:       tmp.Velocity,
+		TrueTrack:      tmp.TrueTrack,
+		VerticalRate:   tmp.VerticalRate,
+		Sensors:        tmp.Sensors,
+		GeoAltitude:    tmp.GeoAltitude,
+		Squawk:         tmp.Squawk,
+		Spi:            tmp.Spi,
+		PositionSource: tmp.PositionSource,
+		Category:       tmp.Category,
+	}
+
+	return nil
+}
+
+// EncodedStatesResponse wraps a StatesResponse to select the encoding of its state vectors when marshalling
+type EncodedStatesResponse struct {
+	StatesResponse
+	Encoding StatesEncoding
+}
+
+// MarshalJSON marshals the response, encoding the state vectors as selected by Encoding
+func (r EncodedStatesResponse) MarshalJSON() ([]byte, error) {
+	if r.Encoding != KeyedEncoding {
+		return json.Marshal(r.StatesResponse)
+	}
+
+	var states []KeyedStateVector
+
+	if r.States != nil {
+		states = make([]KeyedStateVector, len(r.States))
+
+		for i, state := range r.States {
+			states[i] = KeyedStateVector(state)
+		}
+	}
+
+	return json.Marshal(struct {
+		Time   int64              `json:"time"`
+		States []KeyedStateVector `json:"states"`
+	}{r.Time, states})
+}
+
+// UnmarshalJSON unmarshals a response with state vectors in either encoding. Encoding is set to
+// KeyedEncoding when the state vectors are objects.
+func (r *EncodedStatesResponse) UnmarshalJSON(buf []byte) error {
+	var tmp struct {
+		Time   int64             `json:"time"`
+		States []json.RawMessage `json:"states"`
+	}
+
+	if err := json.Unmarshal(buf, &tmp); err != nil {
+		return err
+	}
+
+	r.Time = tmp.Time
+	r.States = nil
+	r.Encoding = PositionalEncoding
+
+	if tmp.States == nil {
+		return nil
+	}
+
+	r.States = make([]StateVector, len(tmp.States))
+
+	for i, raw := range tmp.States {
+		if len(raw) > 0 && raw[0] == '{' {
+			r.Encoding = KeyedEncoding
+
+			if err := json.Unmarshal(raw, (*KeyedStateVector)(&r.States[i])); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := json.Unmarshal(raw, &r.States[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
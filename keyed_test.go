@@ -0,0 +1,98 @@
+package goflight_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestKeyedStateVector_MarshalJSON(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/state_vector.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var vector goflight.StateVector
+
+	if err = json.Unmarshal(data, &vector); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	encoded, err := json.Marshal(goflight.KeyedStateVector(vector))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var fields map[string]interface{}
+
+	if err = json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if fields["icao24"] != "a2e5ec" || fields["baro_altitude"] != 3307.08 {
+		t.Errorf("unexpected keyed fields: %s", encoded)
+	}
+
+	if fields["time_position_rfc3339"] != "2020-04-04T20:15:09Z" {
+		t.Errorf("expected %v to equal 2020-04-04T20:15:09Z", fields["time_position_rfc3339"])
+	}
+
+	if _, ok := fields["category"]; ok {
+		t.Error("expected category to be omitted")
+	}
+
+	var decoded goflight.KeyedStateVector
+
+	if err = json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !reflect.DeepEqual(goflight.StateVector(decoded), vector) {
+		t.Errorf("expected %+v to equal %+v", decoded, vector)
+	}
+}
+
+func TestEncodedStatesResponse_MarshalJSON(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/states.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var response goflight.StatesResponse
+
+	if err = json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, encoding := range []goflight.StatesEncoding{goflight.PositionalEncoding, goflight.KeyedEncoding} {
+		encoded, err := json.Marshal(goflight.EncodedStatesResponse{StatesResponse: response, Encoding: encoding})
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if keyed := strings.Contains(string(encoded), `"icao24"`); keyed != (encoding == goflight.KeyedEncoding) {
+			t.Errorf("unexpected encoding for %v: %s", encoding, encoded)
+		}
+
+		var decoded goflight.EncodedStatesResponse
+
+		if err = json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if decoded.Encoding != encoding {
+			t.Errorf("expected %v to equal %v", decoded.Encoding, encoding)
+		}
+
+		if !reflect.DeepEqual(decoded.StatesResponse, response) {
+			t.Errorf("expected %+v to equal %+v", decoded.StatesResponse, response)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package goflight
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	kmlNamespace   = "http://www.opengis.net/kml/2.2"
+	kmlGxNamespace = "http://www.google.com/kml/ext/2.2"
+	kmlAirborne    = "airborne"
+	kmlGround      = "ground"
+)
+
+// KMLSample is a single timestamped position of a KMLTrack
+type KMLSample struct {
+	Time     time.Time
+	Position Point
+	Altitude *float64 // Altitude in meters. Can be nil, it is interpolated from the surrounding samples in an airborne segment.
+	OnGround bool
+}
+
+// KMLTrack is the trajectory of a single aircraft which can be written as KML
+type KMLTrack struct {
+	Name    string
+	ICAO24  string
+	Samples []KMLSample
+}
+
+type kmlDocument struct {
+	XMLName xml.Name    `xml:"kml"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	XmlnsGx string      `xml:"xmlns:gx,attr"`
+	Name    string      `xml:"Document>name,omitempty"`
+	Styles  []kmlStyle  `xml:"Document>Style"`
+	Folders []kmlFolder `xml:"Document>Folder"`
+}
+
+type kmlStyle struct {
+	ID        string `xml:"id,attr"`
+	LineColor string `xml:"LineStyle>color"`
+	LineWidth int    `xml:"LineStyle>width"`
+	IconColor string `xml:"IconStyle>color"`
+}
+
+type kmlFolder struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name     string   `xml:"name"`
+	StyleURL string   `xml:"styleUrl"`
+	Track    kmlTrack `xml:"gx:Track"`
+}
+
+type kmlTrack struct {
+	AltitudeMode string   `xml:"altitudeMode"`
+	When         []string `xml:"when"`
+	Coord        []string `xml:"gx:coord"`
+}
+
+// NewKMLTrack creates a KMLTrack from the waypoints of a track. Waypoints without position are skipped.
+func NewKMLTrack(t Track) KMLTrack {
+	result := KMLTrack{Name: t.ICAO24, ICAO24: t.ICAO24}
+
+	if t.CallSign != nil && *t.CallSign != "" {
+		result.Name = *t.CallSign
+	}
+
+	for _, waypoint := range t.Path {
+		position, ok := waypoint.Position()
+
+		if !ok {
+			continue
+		}
+
+		result.Samples = append(result.Samples, KMLSample{
+			Time:     time.Unix(waypoint.Time, 0).UTC(),
+			Position: position,
+			Altitude: waypoint.BaroAltitude,
+			OnGround: waypoint.OnGround,
+		})
+	}
+
+	return result
+}
+
+// NewKMLTrackFromStates creates a KMLTrack from accumulated state vectors of a single aircraft. The state vectors
+// are ordered by their position time and state vectors without position are skipped. The altitude is taken from
+// BaroAltitude, or from GeoAltitude when useGeoAltitude is true.
+func NewKMLTrackFromStates(states []StateVector, useGeoAltitude bool) KMLTrack {
+	var result KMLTrack
+
+	for _, state := range states {
+		position, ok := state.Position()
+
+		if !ok {
+			continue
+		}
+
+		if result.ICAO24 == "" {
+			result.ICAO24 = state.ICAO24
+			result.Name = state.ICAO24
+		}
+
+		if state.Callsign != nil && *state.Callsign != "" {
+			result.Name = *state.Callsign
+		}
+
+		altitude := state.BaroAltitude
+
+		if useGeoAltitude {
+			altitude = state.GeoAltitude
+		}
+
+		result.Samples = append(result.Samples, KMLSample{
+			Time:     state.positionTime().UTC(),
+			Position: position,
+			Altitude: altitude,
+			OnGround: state.OnGround,
+		})
+	}
+
+	sort.SliceStable(result.Samples, func(i, j int) bool {
+		return result.Samples[i].Time.Before(result.Samples[j].Time)
+	})
+
+	return result
+}
+
+// placemarks splits the track into a placemark for every ground and airborne segment
+func (t KMLTrack) placemarks() []kmlPlacemark {
+	var placemarks []kmlPlacemark
+	start := 0
+
+	for i := 1; i <= len(t.Samples); i++ {
+		if i < len(t.Samples) && t.Samples[i].OnGround == t.Samples[start].OnGround {
+			continue
+		}
+
+		// Segments share their boundary sample, so the track is drawn without gaps
+		end := i
+
+		if i < len(t.Samples) {
+			end = i + 1
+		}
+
+		placemarks = append(placemarks, newKMLPlacemark(t.Name, t.Samples[start:end]))
+		start = i
+	}
+
+	return placemarks
+}
+
+func newKMLPlacemark(name string, samples []KMLSample) kmlPlacemark {
+	placemark := kmlPlacemark{Name: name, StyleURL: "#" + kmlAirborne}
+	var altitudes []float64
+
+	if samples[0].OnGround {
+		placemark.StyleURL = "#" + kmlGround
+	} else {
+		altitudes = kmlAltitudes(samples)
+	}
+
+	placemark.Track.AltitudeMode = "clampToGround"
+
+	if altitudes != nil {
+		placemark.Track.AltitudeMode = "absolute"
+	}
+
+	for i, sample := range samples {
+		altitude := 0.0
+
+		if altitudes != nil {
+			altitude = altitudes[i]
+		}
+
+		placemark.Track.When = append(placemark.Track.When, sample.Time.UTC().Format(time.RFC3339))
+		placemark.Track.Coord = append(placemark.Track.Coord, fmt.Sprintf(
+			"%s %s %s",
+			strconv.FormatFloat(sample.Position.Longitude, 'f', -1, 64),
+			strconv.FormatFloat(sample.Position.Latitude, 'f', -1, 64),
+			strconv.FormatFloat(altitude, 'f', -1, 64),
+		))
+	}
+
+	return placemark
+}
+
+// kmlAltitudes returns the altitude of every sample, or nil when no sample has an altitude. A missing altitude is
+// interpolated in time between the nearest samples with an altitude, or copied from the nearest sample with an
+// altitude at the start and end, so a single missing altitude doesn't put the whole segment on the ground.
+func kmlAltitudes(samples []KMLSample) []float64 {
+	altitudes := make([]float64, len(samples))
+	previous := -1
+
+	for i, sample := range samples {
+		if sample.Altitude == nil {
+			continue
+		}
+
+		altitudes[i] = *sample.Altitude
+
+		for j := previous + 1; j < i; j++ {
+			if previous < 0 {
+				altitudes[j] = altitudes[i]
+				continue
+			}
+
+			fraction := 0.0
+
+			if span := samples[i].Time.Sub(samples[previous].Time); span > 0 {
+				fraction = float64(samples[j].Time.Sub(samples[previous].Time)) / float64(span)
+			}
+
+			altitudes[j] = altitudes[previous] + fraction*(altitudes[i]-altitudes[previous])
+		}
+
+		previous = i
+	}
+
+	if previous < 0 {
+		return nil
+	}
+
+	for j := previous + 1; j < len(samples); j++ {
+		altitudes[j] = altitudes[previous]
+	}
+
+	return altitudes
+}
+
+// WriteKML writes the tracks as a KML document with a gx:Track for every ground and airborne segment
+func WriteKML(w io.Writer, name string, tracks ...KMLTrack) error {
+	doc := kmlDocument{
+		Xmlns:   kmlNamespace,
+		XmlnsGx: kmlGxNamespace,
+		Name:    name,
+		Styles: []kmlStyle{
+			{ID: kmlAirborne, LineColor: "ff0000ff", LineWidth: 3, IconColor: "ff0000ff"},
+			{ID: kmlGround, LineColor: "ff00ff00", LineWidth: 2, IconColor: "ff00ff00"},
+		},
+	}
+
+	for _, track := range tracks {
+		if len(track.Samples) == 0 {
+			continue
+		}
+
+		doc.Folders = append(doc.Folders, kmlFolder{Name: track.Name, Placemarks: track.placemarks()})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// WriteKMZ writes the tracks as a KMZ archive, which is a zip file containing the KML document as doc.kml
+func WriteKMZ(w io.Writer, name string, tracks ...KMLTrack) error {
+	archive := zip.NewWriter(w)
+	doc, err := archive.Create("doc.kml")
+
+	if err != nil {
+		return err
+	}
+
+	if err = WriteKML(doc, name, tracks...); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
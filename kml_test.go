@@ -0,0 +1,148 @@
+package goflight_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readMockTrack(t *testing.T) goflight.Track {
+	data, err := ioutil.ReadFile("./mocks/track.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var track goflight.Track
+
+	if err = json.Unmarshal(data, &track); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return track
+}
+
+func TestWriteKML(t *testing.T) {
+	track := goflight.NewKMLTrack(readMockTrack(t))
+
+	if track.Name != "ZXP25   " || len(track.Samples) != 5 {
+		t.Fatalf("unexpected KML track: %+v", track)
+	}
+
+	var buf bytes.Buffer
+
+	if err := goflight.WriteKML(&buf, "replay", track); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	kml := buf.String()
+
+	if !strings.HasPrefix(kml, "<?xml") || !strings.Contains(kml, `xmlns:gx="http://www.google.com/kml/ext/2.2"`) {
+		t.Errorf("expected a KML document, got %v", kml)
+	}
+
+	// The track starts on the ground and continues airborne
+	if count := strings.Count(kml, "<gx:Track>"); count != 2 {
+		t.Errorf("expected 2 gx:Track elements, got %v", count)
+	}
+
+	if !strings.Contains(kml, "<styleUrl>#ground</styleUrl>") || !strings.Contains(kml, "<styleUrl>#airborne</styleUrl>") {
+		t.Error("expected ground and airborne placemarks")
+	}
+
+	if !strings.Contains(kml, "<altitudeMode>absolute</altitudeMode>") || !strings.Contains(kml, "<gx:coord>4.7268 52.3371 152.4</gx:coord>") {
+		t.Error("expected airborne segment to use absolute altitudes")
+	}
+
+	if !strings.Contains(kml, "<when>2020-04-04T20:00:00Z</when>") {
+		t.Error("expected timestamps in RFC 3339 format")
+	}
+}
+
+func TestNewKMLTrackFromStates(t *testing.T) {
+	first, second := int64(20), int64(10)
+	states := []goflight.StateVector{
+		{ICAO24: "484ac1", TimePosition: &first, Latitude: floatPtr(52.1), Longitude: floatPtr(4.9), GeoAltitude: floatPtr(1200)},
+		{ICAO24: "484ac1", TimePosition: &second, Latitude: floatPtr(52.0), Longitude: floatPtr(4.8), GeoAltitude: floatPtr(1000)},
+		{ICAO24: "484ac1"},
+	}
+
+	track := goflight.NewKMLTrackFromStates(states, true)
+
+	if len(track.Samples) != 2 {
+		t.Fatalf("expected 2 samples, got %v", len(track.Samples))
+	}
+
+	if track.Samples[0].Time.Unix() != 10 || *track.Samples[0].Altitude != 1000 {
+		t.Errorf("expected samples to be ordered by time, got %+v", track.Samples)
+	}
+}
+
+func TestWriteKMZ(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := goflight.WriteKMZ(&buf, "replay", goflight.NewKMLTrack(readMockTrack(t))); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(archive.File) != 1 || archive.File[0].Name != "doc.kml" {
+		t.Fatal("expected KMZ to contain doc.kml")
+	}
+
+	f, err := archive.File[0].Open()
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defer f.Close()
+	kml, err := ioutil.ReadAll(f)
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !bytes.Contains(kml, []byte("<gx:Track>")) {
+		t.Error("expected doc.kml to contain a gx:Track")
+	}
+}
+
+func TestWriteKML_MissingAltitude(t *testing.T) {
+	start := time.Unix(1586030400, 0)
+	track := goflight.KMLTrack{Name: "KLM31", Samples: []goflight.KMLSample{
+		{Time: start, Position: goflight.Point{Latitude: 52.0, Longitude: 4.0}, Altitude: floatPtr(1000)},
+		{Time: start.Add(10 * time.Second), Position: goflight.Point{Latitude: 52.1, Longitude: 4.1}},
+		{Time: start.Add(40 * time.Second), Position: goflight.Point{Latitude: 52.2, Longitude: 4.2}, Altitude: floatPtr(2000)},
+		{Time: start.Add(50 * time.Second), Position: goflight.Point{Latitude: 52.3, Longitude: 4.3}},
+	}}
+
+	var buf bytes.Buffer
+
+	if err := goflight.WriteKML(&buf, "replay", track); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	kml := buf.String()
+
+	if !strings.Contains(kml, "<altitudeMode>absolute</altitudeMode>") || strings.Contains(kml, "clampToGround") {
+		t.Fatalf("expected the airborne segment to keep absolute altitudes: %v", kml)
+	}
+
+	// The missing altitudes are interpolated between and copied from the samples with an altitude
+	for _, coord := range []string{"4 52 1000", "4.1 52.1 1250", "4.2 52.2 2000", "4.3 52.3 2000"} {
+		if !strings.Contains(kml, "<gx:coord>"+coord+"</gx:coord>") {
+			t.Errorf("expected coordinate %v in %v", coord, kml)
+		}
+	}
+}
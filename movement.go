@@ -0,0 +1,165 @@
+package goflight
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// MovementType describes whether a Movement is a takeoff or landing
+type MovementType int
+
+const (
+	// Takeoff is detected when an aircraft on the ground becomes airborne
+	Takeoff MovementType = iota
+	// Landing is detected when an airborne aircraft is on the ground
+	Landing
+)
+
+// String returns the name of the movement type
+func (t MovementType) String() string {
+	switch t {
+	case Takeoff:
+		return "takeoff"
+	case Landing:
+		return "landing"
+	}
+
+	return fmt.Sprintf("MovementType(%d)", int(t))
+}
+
+// Movement is a takeoff or landing detected by a MovementDetector
+type Movement struct {
+	Type     MovementType
+	ICAO24   string
+	CallSign *string   // Callsign of the aircraft, can be nil.
+	Time     time.Time // Position time of the first state after the transition.
+	Position Point     // Position of the aircraft on the ground, before takeoff or after landing.
+	Heading  float64   // Observed heading of the aircraft while airborne in decimal degrees.
+	Airport  *Airport  // Nearest airport, nil if there is none within the maximum distance.
+	Runway   *Runway   // Runway direction matching the heading, nil if the airport or runway is unknown.
+}
+
+type movementState struct {
+	state    StateVector
+	onGround bool
+	lastSeen time.Time
+}
+
+// MovementDetector detects takeoffs and landings in live snapshots. An aircraft is considered on the ground when
+// it reports a surface position or flies slower than MaxGroundSpeed. A transition between ground and air is
+// only reported when the airborne state is below MaxHeight above the airport and at least MinAirborneSpeed,
+// which filters out glitches in the reported ground status.
+type MovementDetector struct {
+	MaxGroundSpeed     float64       // Aircraft slower than this speed in m/s are considered on the ground.
+	MinAirborneSpeed   float64       // Minimum speed in m/s of the airborne state of a transition, if known.
+	MaxHeight          float64       // Maximum height in meters above the airport of the airborne state of a transition, if known.
+	MaxAirportDistance float64       // Maximum distance in meters of the airport to the position on the ground.
+	MaxRunwayDeviation float64       // Maximum difference in degrees between the heading and the runway heading.
+	Timeout            time.Duration // Aircraft missing from the snapshots for longer than the timeout are forgotten.
+
+	aircraft map[string]*movementState
+}
+
+// NewMovementDetector creates a MovementDetector with default thresholds
+func NewMovementDetector() *MovementDetector {
+	return &MovementDetector{
+		MaxGroundSpeed:     20,
+		MinAirborneSpeed:   30,
+		MaxHeight:          600,
+		MaxAirportDistance: 10000,
+		MaxRunwayDeviation: 20,
+		Timeout:            5 * time.Minute,
+		aircraft:           make(map[string]*movementState),
+	}
+}
+
+// Update evaluates a snapshot and returns the detected movements.
+// Snapshots should be provided in chronological order.
+func (d *MovementDetector) Update(response StatesResponse) []Movement {
+	if d.aircraft == nil {
+		d.aircraft = make(map[string]*movementState)
+	}
+
+	now := time.Unix(response.Time, 0)
+	var movements []Movement
+
+	for _, state := range response.States {
+		if state.Latitude == nil || state.Longitude == nil {
+			continue
+		}
+
+		current := &movementState{state: state, onGround: d.onGround(state), lastSeen: now}
+		previous, ok := d.aircraft[state.ICAO24]
+		d.aircraft[state.ICAO24] = current
+
+		if !ok || previous.onGround == current.onGround {
+			continue
+		}
+
+		if current.onGround {
+			if m, ok := d.movement(Landing, current.state, previous.state, current.state); ok {
+				movements = append(movements, m)
+			}
+		} else if m, ok := d.movement(Takeoff, previous.state, current.state, current.state); ok {
+			movements = append(movements, m)
+		}
+	}
+
+	for icao24, a := range d.aircraft {
+		if now.Sub(a.lastSeen) > d.Timeout {
+			delete(d.aircraft, icao24)
+		}
+	}
+
+	sort.Slice(movements, func(i, j int) bool { return movements[i].ICAO24 < movements[j].ICAO24 })
+
+	return movements
+}
+
+func (d *MovementDetector) onGround(s StateVector) bool {
+	return s.OnGround || (s.Velocity != nil && *s.Velocity < d.MaxGroundSpeed)
+}
+
+// movement checks the thresholds of a transition between the ground and airborne state
+func (d *MovementDetector) movement(typ MovementType, ground, airborne, current StateVector) (Movement, bool) {
+	if airborne.Velocity != nil && *airborne.Velocity < d.MinAirborneSpeed {
+		return Movement{}, false
+	}
+
+	position, _ := ground.Position()
+	airbornePosition, _ := airborne.Position()
+	m := Movement{
+		Type:     typ,
+		ICAO24:   current.ICAO24,
+		CallSign: current.Callsign,
+		Time:     current.positionTime(),
+		Position: position,
+	}
+
+	switch {
+	case airborne.TrueTrack != nil:
+		m.Heading = *airborne.TrueTrack
+	case typ == Takeoff:
+		m.Heading = InitialBearing(position, airbornePosition)
+	default:
+		m.Heading = InitialBearing(airbornePosition, position)
+	}
+
+	elevation := 0.0
+
+	if airport, _, ok := NearestAirport(position, d.MaxAirportDistance); ok {
+		m.Airport = &airport
+		elevation = airport.Elevation
+
+		if runway, ok := airport.RunwayForHeading(m.Heading, d.MaxRunwayDeviation); ok {
+			m.Runway = &runway
+		}
+	}
+
+	if altitude := airborne.altitude(); altitude != nil && *altitude-elevation > d.MaxHeight {
+		return Movement{}, false
+	}
+
+	return m, true
+}
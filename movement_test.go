@@ -0,0 +1,131 @@
+package goflight_test
+
+import (
+	"github.com/marcelblijleven/goflight"
+	"testing"
+	"time"
+)
+
+func movementState(icao24 string, t int64, lat, lon, altitude, velocity, track float64, onGround bool) goflight.StateVector {
+	callsign := "KLM1    "
+
+	return goflight.StateVector{
+		ICAO24:       icao24,
+		Callsign:     &callsign,
+		TimePosition: &t,
+		LastContact:  t,
+		Latitude:     floatPtr(lat),
+		Longitude:    floatPtr(lon),
+		BaroAltitude: floatPtr(altitude),
+		Velocity:     floatPtr(velocity),
+		TrueTrack:    floatPtr(track),
+		OnGround:     onGround,
+	}
+}
+
+func TestMovementDetector_Update(t *testing.T) {
+	snapshots := []goflight.StatesResponse{
+		{Time: 100, States: []goflight.StateVector{
+			movementState("484ac1", 100, 52.36, 4.71, 0, 10, 183, true),
+			movementState("4846e1", 100, 51.48, -0.40, 150, 70, 268, false),
+			movementState("06a2e1", 100, 48.00, 8.00, 11000, 230, 90, false),
+			movementState("c05ed0", 100, 47.00, 6.00, 0, 10, 90, true),
+		}},
+		{Time: 110, States: []goflight.StateVector{
+			// Takeoff from the Polderbaan to the south
+			movementState("484ac1", 110, 52.35, 4.71, 150, 80, 184, false),
+			// Landing at Heathrow without surface position, detected by the speed
+			movementState("4846e1", 110, 51.47, -0.45, 30, 15, 270, false),
+			// A ground status glitch at cruise level is ignored
+			movementState("06a2e1", 110, 48.00, 8.03, 11000, 230, 90, true),
+			// Takeoff without nearby airport
+			movementState("c05ed0", 110, 47.00, 6.01, 100, 60, 90, false),
+		}},
+	}
+
+	d := goflight.NewMovementDetector()
+
+	if movements := d.Update(snapshots[0]); len(movements) != 0 {
+		t.Fatalf("expected no movements: %+v", movements)
+	}
+
+	movements := d.Update(snapshots[1])
+
+	if len(movements) != 3 {
+		t.Fatalf("expected %v movements to equal 3: %+v", len(movements), movements)
+	}
+
+	expected := []struct {
+		icao24  string
+		typ     goflight.MovementType
+		airport string
+		runway  string
+	}{
+		{"4846e1", goflight.Landing, "EGLL", "27"},
+		{"484ac1", goflight.Takeoff, "EHAM", "18"},
+		{"c05ed0", goflight.Takeoff, "", ""},
+	}
+
+	for i, e := range expected {
+		m := movements[i]
+
+		if m.ICAO24 != e.icao24 || m.Type != e.typ || !m.Time.Equal(time.Unix(110, 0)) || *m.CallSign != "KLM1    " {
+			t.Errorf("unexpected movement: %+v", m)
+		}
+
+		if e.airport == "" {
+			if m.Airport != nil || m.Runway != nil {
+				t.Errorf("expected no airport for %v: %+v", m.ICAO24, m.Airport)
+			}
+
+			continue
+		}
+
+		if m.Airport == nil || m.Airport.ICAO != e.airport || m.Runway == nil || m.Runway.Name != e.runway {
+			t.Errorf("expected airport %v and runway %v for %v: %+v %+v", e.airport, e.runway, m.ICAO24, m.Airport, m.Runway)
+		}
+	}
+
+	if movements[1].Position.Latitude != 52.36 || movements[1].Heading != 184 {
+		t.Errorf("unexpected takeoff position or heading: %+v", movements[1])
+	}
+}
+
+func TestMovementDetector_Timeout(t *testing.T) {
+	d := goflight.NewMovementDetector()
+	d.Update(goflight.StatesResponse{Time: 100, States: []goflight.StateVector{movementState("484ac1", 100, 52.36, 4.71, 0, 10, 183, true)}})
+	d.Update(goflight.StatesResponse{Time: 1000})
+
+	movements := d.Update(goflight.StatesResponse{Time: 1010, States: []goflight.StateVector{movementState("484ac1", 1010, 52.35, 4.71, 150, 80, 184, false)}})
+
+	if len(movements) != 0 {
+		t.Errorf("expected the aircraft to be forgotten: %+v", movements)
+	}
+}
+
+func TestMovementType_String(t *testing.T) {
+	if goflight.Takeoff.String() != "takeoff" || goflight.Landing.String() != "landing" || goflight.MovementType(5).String() != "MovementType(5)" {
+		t.Errorf("unexpected movement type names")
+	}
+}
+
+func TestNearestAirport(t *testing.T) {
+	airport, distance, ok := goflight.NearestAirport(goflight.Point{Latitude: 52.31, Longitude: 4.76}, 5000)
+
+	if !ok || airport.ICAO != "EHAM" || distance > 500 {
+		t.Errorf("unexpected airport %v at %v m", airport.ICAO, distance)
+	}
+
+	if _, _, ok = goflight.NearestAirport(goflight.Point{Latitude: 0, Longitude: 0}, 5000); ok {
+		t.Errorf("expected no airport near 0, 0")
+	}
+
+	// Headings wrap around north
+	if runway, ok := airport.RunwayForHeading(359, 20); !ok || runway.Name != "36" {
+		t.Errorf("unexpected runway: %+v", runway)
+	}
+
+	if _, ok := airport.RunwayForHeading(130, 20); ok {
+		t.Errorf("expected no runway for heading 130")
+	}
+}
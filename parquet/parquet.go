@@ -0,0 +1,619 @@
+// Package parquet writes state snapshots to Apache Parquet files.
+//
+// Every row is a state vector, extended with the time of the snapshot it belongs to. Pointer fields of the
+// StateVector are written as nullable columns and Sensors is written as a nullable list of int32.
+// The format is described at https://github.com/apache/parquet-format.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"io"
+	"math"
+)
+
+// DefaultRowGroupSize is the number of rows in a row group when no size is provided to NewWriter
+const DefaultRowGroupSize = 100000
+
+// Codec is the compression codec used for the data pages
+type Codec int32
+
+const (
+	// Uncompressed writes the data pages without compression
+	Uncompressed Codec = 0
+	// Gzip compresses the data pages with gzip
+	Gzip Codec = 2
+)
+
+// ErrWriterClosed is returned when writing to a closed Writer
+var ErrWriterClosed = errors.New("the parquet writer is closed")
+
+var magic = []byte("PAR1")
+
+// Parquet enum values, see parquet.thrift
+const (
+	typeBoolean   = 0
+	typeInt32     = 1
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+	repetitionRepeated = 2
+
+	convertedUTF8 = 0
+	convertedList = 3
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+)
+
+type column struct {
+	name      string
+	typ       int32
+	utf8      bool
+	optional  bool
+	list      bool
+	defs      []uint8
+	reps      []uint8
+	bools     []bool
+	values    bytes.Buffer
+	numValues int
+}
+
+type columnChunk struct {
+	path             []string
+	typ              int32
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+	offset           int64
+}
+
+type rowGroup struct {
+	columns   []columnChunk
+	totalSize int64
+	numRows   int64
+}
+
+// Writer writes state snapshots to a Parquet file. Rows are buffered in memory and written as a row group
+// when the row group size is reached. Close must be called to write the file footer.
+type Writer struct {
+	Compression Codec // Compression codec of the data pages, must be set before the first write.
+
+	w            io.Writer
+	offset       int64
+	rowGroupSize int
+	rows         int
+	columns      []*column
+	rowGroups    []rowGroup
+	footer       []byte
+	err          error
+	closed       bool
+}
+
+// NewWriter creates a Writer which writes row groups of rowGroupSize rows to w.
+// A rowGroupSize of zero or less uses DefaultRowGroupSize.
+func NewWriter(w io.Writer, rowGroupSize int) *Writer {
+	if rowGroupSize <= 0 {
+		rowGroupSize = DefaultRowGroupSize
+	}
+
+	return &Writer{
+		w:            w,
+		rowGroupSize: rowGroupSize,
+		columns: []*column{
+			{name: "snapshot_time", typ: typeInt64},
+			{name: "icao24", typ: typeByteArray, utf8: true},
+			{name: "callsign", typ: typeByteArray, utf8: true, optional: true},
+			{name: "origin_country", typ: typeByteArray, utf8: true},
+			{name: "time_position", typ: typeInt64, optional: true},
+			{name: "last_contact", typ: typeInt64},
+			{name: "longitude", typ: typeDouble, optional: true},
+			{name: "latitude", typ: typeDouble, optional: true},
+			{name: "baro_altitude", typ: typeDouble, optional: true},
+			{name: "on_ground", typ: typeBoolean},
+			{name: "velocity", typ: typeDouble, optional: true},
+			{name: "true_track", typ: typeDouble, optional: true},
+			{name: "vertical_rate", typ: typeDouble, optional: true},
+			{name: "sensors", typ: typeInt32, optional: true, list: true},
+			{name: "geo_altitude", typ: typeDouble, optional: true},
+			{name: "squawk", typ: typeByteArray, utf8: true, optional: true},
+			{name: "spi", typ: typeBoolean, optional: true},
+			{name: "position_source", typ: typeInt32},
+			{name: "category", typ: typeInt32, optional: true},
+		},
+	}
+}
+
+// WriteSnapshot adds a row for every state vector in the snapshot
+func (w *Writer) WriteSnapshot(snapshot goflight.StatesResponse) error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if w.closed || w.footer != nil {
+		return ErrWriterClosed
+	}
+
+	for i := range snapshot.States {
+		w.addRow(snapshot.Time, &snapshot.States[i])
+
+		if w.rows >= w.rowGroupSize {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) addRow(snapshotTime int64, s *goflight.StateVector) {
+	c := w.columns
+	c[0].addInt64(&snapshotTime)
+	c[1].addString(&s.ICAO24)
+	c[2].addString(s.Callsign)
+	c[3].addString(&s.OriginCountry)
+	c[4].addInt64(s.TimePosition)
+	c[5].addInt64(&s.LastContact)
+	c[6].addDouble(s.Longitude)
+	c[7].addDouble(s.Latitude)
+	c[8].addDouble(s.BaroAltitude)
+	c[9].addBool(&s.OnGround)
+	c[10].addDouble(s.Velocity)
+	c[11].addDouble(s.TrueTrack)
+	c[12].addDouble(s.VerticalRate)
+	c[13].addInt32List(s.Sensors)
+	c[14].addDouble(s.GeoAltitude)
+	c[15].addString(s.Squawk)
+	c[16].addBool(s.Spi)
+	c[17].addInt32(&s.PositionSource)
+	c[18].addInt32(s.Category)
+	w.rows++
+}
+
+// Flush writes the buffered rows as a row group. When writing the row group fails, the file can't be completed
+// and the error is returned by every later call.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if w.closed || w.footer != nil {
+		return ErrWriterClosed
+	}
+
+	if w.rows == 0 {
+		return nil
+	}
+
+	if w.err = w.writeMagic(); w.err != nil {
+		return w.err
+	}
+
+	group := rowGroup{numRows: int64(w.rows)}
+
+	for _, c := range w.columns {
+		chunk, err := w.writeColumnChunk(c)
+
+		if err != nil {
+			// The chunks written so far are part of the file, so the row group can't be written again
+			w.err = err
+			return err
+		}
+
+		group.columns = append(group.columns, chunk)
+		group.totalSize += chunk.uncompressedSize
+	}
+
+	// The columns are only reset when the whole row group is written
+	for _, c := range w.columns {
+		c.reset()
+	}
+
+	w.rowGroups = append(w.rowGroups, group)
+	w.rows = 0
+
+	return nil
+}
+
+// Close flushes the buffered rows and writes the file footer. It does not close the underlying writer.
+// When writing the footer fails, Close can be called again to write the remainder of the footer. When writing a
+// row group failed, the error is returned and no footer is written.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if w.closed {
+		return nil
+	}
+
+	if w.footer == nil {
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if w.err = w.writeMagic(); w.err != nil {
+			return w.err
+		}
+
+		metadata := w.fileMetaData()
+		var length [4]byte
+		binary.LittleEndian.PutUint32(length[:], uint32(len(metadata)))
+		w.footer = append(append(metadata, length[:]...), magic...)
+	}
+
+	// The footer is consumed as it is written, so a retry continues where the failed write stopped
+	for len(w.footer) > 0 {
+		n, err := w.w.Write(w.footer)
+		w.offset += int64(n)
+		w.footer = w.footer[n:]
+
+		if err != nil {
+			return err
+		}
+
+		if n == 0 {
+			return io.ErrShortWrite
+		}
+	}
+
+	w.closed = true
+
+	return nil
+}
+
+func (w *Writer) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+
+	return err
+}
+
+func (w *Writer) writeMagic() error {
+	if w.offset > 0 {
+		return nil
+	}
+
+	return w.write(magic)
+}
+
+func (w *Writer) writeColumnChunk(c *column) (columnChunk, error) {
+	page := c.pageData()
+	data := page
+
+	if w.Compression == Gzip {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+
+		if _, err := gz.Write(page); err != nil {
+			return columnChunk{}, err
+		}
+
+		if err := gz.Close(); err != nil {
+			return columnChunk{}, err
+		}
+
+		data = buf.Bytes()
+	}
+
+	var header compactWriter
+	header.structBegin()
+	header.i32Field(1, pageTypeData)
+	header.i32Field(2, int32(len(page)))
+	header.i32Field(3, int32(len(data)))
+	header.structField(5)
+	header.i32Field(1, int32(c.numValues))
+	header.i32Field(2, encodingPlain)
+	header.i32Field(3, encodingRLE)
+	header.i32Field(4, encodingRLE)
+	header.structEnd()
+	header.structEnd()
+
+	chunk := columnChunk{
+		path:             c.path(),
+		typ:              c.typ,
+		numValues:        int64(c.numValues),
+		uncompressedSize: int64(header.buf.Len() + len(page)),
+		compressedSize:   int64(header.buf.Len() + len(data)),
+		offset:           w.offset,
+	}
+
+	if err := w.write(header.buf.Bytes()); err != nil {
+		return columnChunk{}, err
+	}
+
+	if err := w.write(data); err != nil {
+		return columnChunk{}, err
+	}
+
+	return chunk, nil
+}
+
+func (w *Writer) fileMetaData() []byte {
+	var numRows int64
+
+	for _, group := range w.rowGroups {
+		numRows += group.numRows
+	}
+
+	var c compactWriter
+	c.structBegin()
+	c.i32Field(1, 1)
+
+	// Schema, flattened depth-first
+	elements := 1
+
+	for _, col := range w.columns {
+		elements++
+
+		if col.list {
+			elements += 2
+		}
+	}
+
+	c.listField(2, elements, compactStruct)
+	c.structBegin()
+	c.stringField(4, "schema")
+	c.i32Field(5, int32(len(w.columns)))
+	c.structEnd()
+
+	for _, col := range w.columns {
+		col.writeSchema(&c)
+	}
+
+	c.i64Field(3, numRows)
+	c.listField(4, len(w.rowGroups), compactStruct)
+
+	for _, group := range w.rowGroups {
+		c.structBegin()
+		c.listField(1, len(group.columns), compactStruct)
+
+		for _, chunk := range group.columns {
+			c.structBegin()
+			c.i64Field(2, chunk.offset)
+			c.structField(3)
+			c.i32Field(1, chunk.typ)
+			c.listField(2, 2, compactI32)
+			c.zigzag(encodingPlain)
+			c.zigzag(encodingRLE)
+			c.listField(3, len(chunk.path), compactBinary)
+
+			for _, p := range chunk.path {
+				c.binary(p)
+			}
+
+			c.i32Field(4, int32(w.Compression))
+			c.i64Field(5, chunk.numValues)
+			c.i64Field(6, chunk.uncompressedSize)
+			c.i64Field(7, chunk.compressedSize)
+			c.i64Field(9, chunk.offset)
+			c.structEnd()
+			c.structEnd()
+		}
+
+		c.i64Field(2, group.totalSize)
+		c.i64Field(3, group.numRows)
+		c.structEnd()
+	}
+
+	c.stringField(6, "goflight")
+	c.structEnd()
+
+	return c.buf.Bytes()
+}
+
+func (c *column) path() []string {
+	if c.list {
+		return []string{c.name, "list", "element"}
+	}
+
+	return []string{c.name}
+}
+
+func (c *column) maxDefinitionLevel() uint8 {
+	switch {
+	case c.list:
+		return 2
+	case c.optional:
+		return 1
+	}
+
+	return 0
+}
+
+func (c *column) writeSchema(w *compactWriter) {
+	if c.list {
+		// The three-level list structure from the Parquet LogicalTypes documentation
+		w.structBegin()
+		w.i32Field(3, repetitionOptional)
+		w.stringField(4, c.name)
+		w.i32Field(5, 1)
+		w.i32Field(6, convertedList)
+		w.structEnd()
+
+		w.structBegin()
+		w.i32Field(3, repetitionRepeated)
+		w.stringField(4, "list")
+		w.i32Field(5, 1)
+		w.structEnd()
+
+		w.structBegin()
+		w.i32Field(1, c.typ)
+		w.i32Field(3, repetitionRequired)
+		w.stringField(4, "element")
+		w.structEnd()
+
+		return
+	}
+
+	w.structBegin()
+	w.i32Field(1, c.typ)
+
+	if c.optional {
+		w.i32Field(3, repetitionOptional)
+	} else {
+		w.i32Field(3, repetitionRequired)
+	}
+
+	w.stringField(4, c.name)
+
+	if c.utf8 {
+		w.i32Field(6, convertedUTF8)
+	}
+
+	w.structEnd()
+}
+
+func (c *column) reset() {
+	c.defs = c.defs[:0]
+	c.reps = c.reps[:0]
+	c.bools = c.bools[:0]
+	c.values.Reset()
+	c.numValues = 0
+}
+
+// level records the definition level of a value, it returns false when the value is null
+func (c *column) level(present bool) bool {
+	c.numValues++
+
+	if !c.optional {
+		return true
+	}
+
+	if present {
+		c.defs = append(c.defs, 1)
+	} else {
+		c.defs = append(c.defs, 0)
+	}
+
+	return present
+}
+
+func (c *column) addInt64(v *int64) {
+	if c.level(v != nil) {
+		var tmp [8]byte
+		binary.LittleEndian.PutUint64(tmp[:], uint64(*v))
+		c.values.Write(tmp[:])
+	}
+}
+
+func (c *column) addInt32(v *int) {
+	if c.level(v != nil) {
+		var tmp [4]byte
+		binary.LittleEndian.PutUint32(tmp[:], uint32(int32(*v)))
+		c.values.Write(tmp[:])
+	}
+}
+
+func (c *column) addDouble(v *float64) {
+	if c.level(v != nil) {
+		var tmp [8]byte
+		binary.LittleEndian.PutUint64(tmp[:], math.Float64bits(*v))
+		c.values.Write(tmp[:])
+	}
+}
+
+func (c *column) addString(v *string) {
+	if c.level(v != nil) {
+		var tmp [4]byte
+		binary.LittleEndian.PutUint32(tmp[:], uint32(len(*v)))
+		c.values.Write(tmp[:])
+		c.values.WriteString(*v)
+	}
+}
+
+func (c *column) addBool(v *bool) {
+	if c.level(v != nil) {
+		c.bools = append(c.bools, *v)
+	}
+}
+
+func (c *column) addInt32List(v *[]int) {
+	switch {
+	case v == nil:
+		c.numValues++
+		c.reps = append(c.reps, 0)
+		c.defs = append(c.defs, 0)
+	case len(*v) == 0:
+		c.numValues++
+		c.reps = append(c.reps, 0)
+		c.defs = append(c.defs, 1)
+	default:
+		for i, value := range *v {
+			var tmp [4]byte
+			binary.LittleEndian.PutUint32(tmp[:], uint32(int32(value)))
+			c.values.Write(tmp[:])
+			c.numValues++
+			c.defs = append(c.defs, 2)
+
+			if i == 0 {
+				c.reps = append(c.reps, 0)
+			} else {
+				c.reps = append(c.reps, 1)
+			}
+		}
+	}
+}
+
+// pageData returns the levels and values of the column in the data page v1 layout
+func (c *column) pageData() []byte {
+	var buf bytes.Buffer
+
+	if c.list {
+		writeLevels(&buf, c.reps)
+	}
+
+	if c.maxDefinitionLevel() > 0 {
+		writeLevels(&buf, c.defs)
+	}
+
+	if c.typ == typeBoolean {
+		packed := make([]byte, (len(c.bools)+7)/8)
+
+		for i, b := range c.bools {
+			if b {
+				packed[i/8] |= 1 << uint(i%8)
+			}
+		}
+
+		buf.Write(packed)
+	}
+
+	buf.Write(c.values.Bytes())
+
+	return buf.Bytes()
+}
+
+// writeLevels writes the levels using RLE runs of the RLE/bit-packing hybrid encoding, prefixed by their length.
+// Levels are at most 2, so every run value fits in a single byte.
+func writeLevels(buf *bytes.Buffer, levels []uint8) {
+	var encoded bytes.Buffer
+	var tmp [binary.MaxVarintLen64]byte
+
+	for i := 0; i < len(levels); {
+		j := i + 1
+
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+
+		n := binary.PutUvarint(tmp[:], uint64(j-i)<<1)
+		encoded.Write(tmp[:n])
+		encoded.WriteByte(levels[i])
+		i = j
+	}
+
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(encoded.Len()))
+	buf.Write(length[:])
+	buf.Write(encoded.Bytes())
+}
@@ -0,0 +1,381 @@
+package parquet_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"github.com/marcelblijleven/goflight"
+	"github.com/marcelblijleven/goflight/parquet"
+	"io/ioutil"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "write the golden files")
+
+// thriftStruct is a decoded thrift compact struct, keyed by field id
+type thriftStruct map[int16]interface{}
+
+type compactReader struct {
+	buf []byte
+	pos int
+}
+
+func (r *compactReader) byte() byte {
+	b := r.buf[r.pos]
+	r.pos++
+
+	return b
+}
+
+func (r *compactReader) varint() uint64 {
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	r.pos += n
+
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.varint()
+
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(typ byte) interface{} {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case 5, 6:
+		return r.zigzag()
+	case 8:
+		n := int(r.varint())
+		v := string(r.buf[r.pos : r.pos+n])
+		r.pos += n
+
+		return v
+	case 9:
+		header := r.byte()
+		size := int(header >> 4)
+
+		if size == 15 {
+			size = int(r.varint())
+		}
+
+		list := make([]interface{}, size)
+
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+
+		return list
+	case 12:
+		return r.structValue()
+	}
+
+	panic("unsupported thrift type")
+}
+
+func (r *compactReader) structValue() thriftStruct {
+	result := thriftStruct{}
+	var id int16
+
+	for {
+		header := r.byte()
+
+		if header == 0 {
+			return result
+		}
+
+		if delta := header >> 4; delta != 0 {
+			id += int16(delta)
+		} else {
+			id = int16(r.zigzag())
+		}
+
+		result[id] = r.value(header & 0x0f)
+	}
+}
+
+func readLevels(data []byte) ([]uint8, []byte) {
+	length := binary.LittleEndian.Uint32(data)
+	r := &compactReader{buf: data[4 : 4+length]}
+	var levels []uint8
+
+	for r.pos < len(r.buf) {
+		run := int(r.varint() >> 1)
+		value := r.byte()
+
+		for i := 0; i < run; i++ {
+			levels = append(levels, value)
+		}
+	}
+
+	return levels, data[4+length:]
+}
+
+type parquetFile struct {
+	data     []byte
+	metadata thriftStruct
+}
+
+func openParquet(t *testing.T, data []byte) parquetFile {
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatal("expected file to start and end with PAR1")
+	}
+
+	length := binary.LittleEndian.Uint32(data[len(data)-8:])
+	footer := data[len(data)-8-int(length) : len(data)-8]
+
+	return parquetFile{data: data, metadata: (&compactReader{buf: footer}).structValue()}
+}
+
+// page returns the decompressed data page of a column in a row group
+func (f parquetFile) page(t *testing.T, group, column int) []byte {
+	chunk := f.metadata[4].([]interface{})[group].(thriftStruct)[1].([]interface{})[column].(thriftStruct)
+	meta := chunk[3].(thriftStruct)
+	r := &compactReader{buf: f.data, pos: int(meta[9].(int64))}
+	header := r.structValue()
+	data := f.data[r.pos : r.pos+int(header[3].(int64))]
+
+	if meta[4].(int64) == int64(parquet.Gzip) {
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if data, err = ioutil.ReadAll(gz); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if int64(len(data)) != header[2].(int64) {
+		t.Fatalf("expected page size %v to equal %v", len(data), header[2])
+	}
+
+	return data
+}
+
+func readSnapshot(t *testing.T) goflight.StatesResponse {
+	data, err := ioutil.ReadFile("../mocks/states.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var response goflight.StatesResponse
+
+	if err = json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	sensors, category := []int{1, 2}, 4
+	response.States[0].Sensors = &sensors
+	response.States[0].Category = &category
+	empty := []int{}
+	response.States[1].Sensors = &empty
+
+	return response
+}
+
+func TestWriter(t *testing.T) {
+	for _, codec := range []parquet.Codec{parquet.Uncompressed, parquet.Gzip} {
+		snapshot := readSnapshot(t)
+		var buf bytes.Buffer
+		w := parquet.NewWriter(&buf, 4)
+		w.Compression = codec
+
+		if err := w.WriteSnapshot(snapshot); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if err := w.Close(); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if err := w.WriteSnapshot(snapshot); err != parquet.ErrWriterClosed {
+			t.Errorf("expected error to be: %v", parquet.ErrWriterClosed.Error())
+		}
+
+		f := openParquet(t, buf.Bytes())
+
+		if rows := f.metadata[3].(int64); rows != 6 {
+			t.Errorf("expected %v rows to equal 6", rows)
+		}
+
+		if groups := len(f.metadata[4].([]interface{})); groups != 2 {
+			t.Fatalf("expected %v row groups to equal 2", groups)
+		}
+
+		schema := f.metadata[2].([]interface{})
+
+		if len(schema) != 22 || schema[2].(thriftStruct)[4] != "icao24" || schema[16].(thriftStruct)[4] != "element" {
+			t.Errorf("unexpected schema: %v", schema)
+		}
+
+		// icao24 is a required byte array column
+		page := f.page(t, 1, 1)
+
+		if !bytes.Equal(page, []byte("\x06\x00\x00\x00406d21\x06\x00\x00\x00485b44")) {
+			t.Errorf("unexpected icao24 page in second row group: %q", page)
+		}
+
+		// sensors is a list with repetition and definition levels
+		reps, rest := readLevels(f.page(t, 0, 13))
+		defs, values := readLevels(rest)
+
+		if !bytes.Equal(reps, []byte{0, 1, 0, 0, 0}) || !bytes.Equal(defs, []byte{2, 2, 1, 0, 0}) {
+			t.Errorf("unexpected sensors levels: %v %v", reps, defs)
+		}
+
+		if !bytes.Equal(values, []byte{1, 0, 0, 0, 2, 0, 0, 0}) {
+			t.Errorf("unexpected sensors values: %v", values)
+		}
+
+		// baro_altitude is an optional double column, the second state has no altitude
+		defs, values = readLevels(f.page(t, 0, 8))
+
+		if !bytes.Equal(defs, []byte{1, 0, 1, 1}) || len(values) != 24 {
+			t.Errorf("unexpected baro_altitude page: %v %v", defs, values)
+		}
+	}
+}
+
+func TestWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := parquet.NewWriter(&buf, 0).Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	f := openParquet(t, buf.Bytes())
+
+	if rows := f.metadata[3].(int64); rows != 0 {
+		t.Errorf("expected %v rows to equal 0", rows)
+	}
+}
+
+// failingWriter accepts limit bytes and fails every write after that
+type failingWriter struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (w *failingWriter) Write(b []byte) (int, error) {
+	if remaining := w.limit - w.buf.Len(); len(b) > remaining {
+		w.buf.Write(b[:remaining])
+
+		return remaining, errors.New("write failed")
+	}
+
+	return w.buf.Write(b)
+}
+
+func TestWriter_CloseRetry(t *testing.T) {
+	var expected bytes.Buffer
+	w := parquet.NewWriter(&expected, 0)
+
+	if err := w.WriteSnapshot(readSnapshot(t)); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Fail halfway through the footer, after all row groups have been written
+	failing := &failingWriter{limit: expected.Len() - 100}
+	w = parquet.NewWriter(failing, 0)
+
+	if err := w.WriteSnapshot(readSnapshot(t)); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := w.Close(); err == nil {
+		t.Fatal("expected the footer write to fail")
+	}
+
+	if err := w.WriteSnapshot(readSnapshot(t)); err != parquet.ErrWriterClosed {
+		t.Errorf("expected error to be: %v", parquet.ErrWriterClosed.Error())
+	}
+
+	failing.limit = expected.Len()
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !bytes.Equal(failing.buf.Bytes(), expected.Bytes()) {
+		t.Error("expected the retried close to complete the file")
+	}
+
+	if err := w.WriteSnapshot(readSnapshot(t)); err != parquet.ErrWriterClosed {
+		t.Errorf("expected error to be: %v", parquet.ErrWriterClosed.Error())
+	}
+}
+
+// TestWriter_Golden compares the output with testdata/states.parquet, which is checked with reference Parquet
+// readers by testdata/check_pyarrow.py. Run the tests with -update to write the golden file after a change to
+// the writer, and check it again.
+func TestWriter_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	w := parquet.NewWriter(&buf, 4)
+
+	if err := w.WriteSnapshot(readSnapshot(t)); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if *update {
+		if err := ioutil.WriteFile("testdata/states.parquet", buf.Bytes(), 0644); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	golden, err := ioutil.ReadFile("testdata/states.parquet")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !bytes.Equal(buf.Bytes(), golden) {
+		t.Error("expected the output to equal testdata/states.parquet")
+	}
+}
+
+func TestWriter_RowGroupError(t *testing.T) {
+	// Fail in the middle of the first row group, after some column chunks have been written
+	failing := &failingWriter{limit: 200}
+	w := parquet.NewWriter(failing, 4)
+	err := w.WriteSnapshot(readSnapshot(t))
+
+	if err == nil {
+		t.Fatal("expected the row group write to fail")
+	}
+
+	failing.limit = 1 << 20
+	written := failing.buf.Len()
+
+	if flushErr := w.Flush(); flushErr != err {
+		t.Errorf("expected %v to equal %v", flushErr, err)
+	}
+
+	if closeErr := w.Close(); closeErr != err {
+		t.Errorf("expected %v to equal %v", closeErr, err)
+	}
+
+	if writeErr := w.WriteSnapshot(readSnapshot(t)); writeErr != err {
+		t.Errorf("expected %v to equal %v", writeErr, err)
+	}
+
+	if failing.buf.Len() != written {
+		t.Errorf("expected nothing to be written after the failed row group, got %v more bytes", failing.buf.Len()-written)
+	}
+}
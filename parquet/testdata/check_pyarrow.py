@@ -0,0 +1,32 @@
+"""Reads states.parquet with pyarrow to check the writer output against a reference reader.
+
+Usage: python check_pyarrow.py [path]
+"""
+import sys
+
+import pyarrow.parquet as pq
+
+path = sys.argv[1] if len(sys.argv) > 1 else "states.parquet"
+metadata = pq.read_metadata(path)
+
+assert metadata.num_rows == 6, metadata.num_rows
+assert metadata.num_row_groups == 2, metadata.num_row_groups
+
+table = pq.read_table(path)
+assert table.column_names == [
+    "snapshot_time", "icao24", "callsign", "origin_country", "time_position", "last_contact",
+    "longitude", "latitude", "baro_altitude", "on_ground", "velocity", "true_track", "vertical_rate",
+    "sensors", "geo_altitude", "squawk", "spi", "position_source", "category",
+], table.column_names
+
+rows = table.to_pylist()
+assert [row["icao24"] for row in rows] == ["484ac1", "4846e1", "06a2e1", "c05ed0", "406d21", "485b44"]
+assert all(row["snapshot_time"] == 1586031310 for row in rows)
+assert rows[0]["callsign"] == "ZXP25   "
+assert rows[0]["longitude"] == 5.4945 and rows[0]["latitude"] == 51.8122
+assert rows[0]["sensors"] == [1, 2] and rows[1]["sensors"] == [] and rows[2]["sensors"] is None
+assert rows[0]["category"] == 4 and rows[1]["category"] is None
+assert rows[1]["baro_altitude"] is None and rows[1]["on_ground"] is True
+assert rows[1]["squawk"] is None and rows[5]["squawk"] == "0727"
+
+print("ok")
@@ -0,0 +1,95 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol types, see
+// https://github.com/apache/thrift/blob/master/doc/specs/thrift-compact-protocol.md
+const (
+	compactI32    = 5
+	compactI64    = 6
+	compactBinary = 8
+	compactList   = 9
+	compactStruct = 12
+)
+
+// compactWriter writes the subset of the thrift compact protocol used by the Parquet metadata
+type compactWriter struct {
+	buf     bytes.Buffer
+	lastIDs []int16
+	lastID  int16
+}
+
+func (c *compactWriter) varint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	c.buf.Write(tmp[:n])
+}
+
+func (c *compactWriter) zigzag(v int64) {
+	c.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (c *compactWriter) fieldHeader(id int16, typ byte) {
+	if delta := id - c.lastID; delta > 0 && delta <= 15 {
+		c.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		c.buf.WriteByte(typ)
+		c.zigzag(int64(id))
+	}
+
+	c.lastID = id
+}
+
+func (c *compactWriter) structBegin() {
+	c.lastIDs = append(c.lastIDs, c.lastID)
+	c.lastID = 0
+}
+
+func (c *compactWriter) structEnd() {
+	c.buf.WriteByte(0)
+	c.lastID = c.lastIDs[len(c.lastIDs)-1]
+	c.lastIDs = c.lastIDs[:len(c.lastIDs)-1]
+}
+
+func (c *compactWriter) listHeader(size int, elemType byte) {
+	if size < 15 {
+		c.buf.WriteByte(byte(size)<<4 | elemType)
+		return
+	}
+
+	c.buf.WriteByte(0xf0 | elemType)
+	c.varint(uint64(size))
+}
+
+func (c *compactWriter) i32Field(id int16, v int32) {
+	c.fieldHeader(id, compactI32)
+	c.zigzag(int64(v))
+}
+
+func (c *compactWriter) i64Field(id int16, v int64) {
+	c.fieldHeader(id, compactI64)
+	c.zigzag(v)
+}
+
+func (c *compactWriter) binary(v string) {
+	c.varint(uint64(len(v)))
+	c.buf.WriteString(v)
+}
+
+func (c *compactWriter) stringField(id int16, v string) {
+	c.fieldHeader(id, compactBinary)
+	c.binary(v)
+}
+
+func (c *compactWriter) structField(id int16) {
+	c.fieldHeader(id, compactStruct)
+	c.structBegin()
+}
+
+func (c *compactWriter) listField(id int16, size int, elemType byte) {
+	c.fieldHeader(id, compactList)
+	c.listHeader(size, elemType)
+}
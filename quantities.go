@@ -0,0 +1,75 @@
+package goflight
+
+import "github.com/marcelblijleven/goflight/units"
+
+// AltitudeFeet returns the barometric altitude in feet, ok is false if the altitude is unknown
+func (s StateVector) AltitudeFeet() (feet float64, ok bool) {
+	if s.BaroAltitude == nil {
+		return 0, false
+	}
+
+	return units.MetersToFeet(*s.BaroAltitude), true
+}
+
+// GeoAltitudeFeet returns the geometric altitude in feet, ok is false if the altitude is unknown
+func (s StateVector) GeoAltitudeFeet() (feet float64, ok bool) {
+	if s.GeoAltitude == nil {
+		return 0, false
+	}
+
+	return units.MetersToFeet(*s.GeoAltitude), true
+}
+
+// FlightLevel returns the flight level based on the barometric altitude, ok is false if the altitude is unknown
+func (s StateVector) FlightLevel() (flightLevel int, ok bool) {
+	if s.BaroAltitude == nil {
+		return 0, false
+	}
+
+	return units.FlightLevel(*s.BaroAltitude), true
+}
+
+// SpeedKnots returns the velocity over ground in knots, ok is false if the velocity is unknown
+func (s StateVector) SpeedKnots() (knots float64, ok bool) {
+	if s.Velocity == nil {
+		return 0, false
+	}
+
+	return units.MetersPerSecondToKnots(*s.Velocity), true
+}
+
+// VerticalRateFeetPerMinute returns the vertical rate in ft/min, ok is false if the vertical rate is unknown
+func (s StateVector) VerticalRateFeetPerMinute() (feetPerMinute float64, ok bool) {
+	if s.VerticalRate == nil {
+		return 0, false
+	}
+
+	return units.MetersPerSecondToFeetPerMinute(*s.VerticalRate), true
+}
+
+// TrackCardinal returns the true track as a compass direction, ok is false if the true track is unknown
+func (s StateVector) TrackCardinal() (direction string, ok bool) {
+	if s.TrueTrack == nil {
+		return "", false
+	}
+
+	return units.Cardinal(*s.TrueTrack), true
+}
+
+// DepartureDistanceNauticalMiles returns the horizontal distance to the estimated departure airport in nautical miles
+func (f Flight) DepartureDistanceNauticalMiles() (nauticalMiles float64, ok bool) {
+	if f.EstDepartureAirportHorizontalDistance == nil {
+		return 0, false
+	}
+
+	return units.MetersToNauticalMiles(float64(*f.EstDepartureAirportHorizontalDistance)), true
+}
+
+// ArrivalDistanceNauticalMiles returns the horizontal distance to the estimated arrival airport in nautical miles
+func (f Flight) ArrivalDistanceNauticalMiles() (nauticalMiles float64, ok bool) {
+	if f.EstArrivalAirportHorizontalDistance == nil {
+		return 0, false
+	}
+
+	return units.MetersToNauticalMiles(float64(*f.EstArrivalAirportHorizontalDistance)), true
+}
@@ -0,0 +1,73 @@
+package goflight_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"math"
+	"testing"
+)
+
+func TestStateVector_Quantities(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/state_vector.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var vector goflight.StateVector
+
+	if err = json.Unmarshal(data, &vector); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if feet, ok := vector.AltitudeFeet(); !ok || math.Abs(feet-10850) > 1 {
+		t.Errorf("expected %v to be approximately 10850", feet)
+	}
+
+	if fl, ok := vector.FlightLevel(); !ok || fl != 109 {
+		t.Errorf("expected %v to equal 109", fl)
+	}
+
+	if knots, ok := vector.SpeedKnots(); !ok || math.Abs(knots-278.75) > 0.01 {
+		t.Errorf("expected %v to be approximately 278.75", knots)
+	}
+
+	if fpm, ok := vector.VerticalRateFeetPerMinute(); !ok || math.Abs(fpm+960.6) > 0.1 {
+		t.Errorf("expected %v to be approximately -960.6", fpm)
+	}
+
+	if direction, ok := vector.TrackCardinal(); !ok || direction != "SE" {
+		t.Errorf("expected %v to equal SE", direction)
+	}
+}
+
+func TestStateVector_Quantities_NilValues(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/state_vector_null_values.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var vector goflight.StateVector
+
+	if err = json.Unmarshal(data, &vector); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, ok := vector.AltitudeFeet(); ok {
+		t.Error("expected altitude to be unknown")
+	}
+
+	if _, ok := vector.FlightLevel(); ok {
+		t.Error("expected flight level to be unknown")
+	}
+
+	if _, ok := vector.SpeedKnots(); ok {
+		t.Error("expected speed to be unknown")
+	}
+
+	if _, ok := vector.VerticalRateFeetPerMinute(); ok {
+		t.Error("expected vertical rate to be unknown")
+	}
+}
@@ -0,0 +1,196 @@
+// Package recorder continuously polls the Opensky API for state snapshots and writes them to a Sink, to build a
+// historical archive.
+//
+// Snapshots are written as they are received. Polling faster than the API updates returns the same snapshot
+// again, those duplicates are skipped. When two consecutive snapshots are further apart than the maximum gap,
+// the gap is reported. The time of the last written snapshot can be kept in a checkpoint file, so a restarted
+// recorder continues where it stopped and reports the gap caused by the restart.
+package recorder
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultInterval is the polling interval when no interval is set, the time resolution of anonymous users
+const DefaultInterval = 10 * time.Second
+
+// Source returns the current state snapshot. The States service of a goflight.Client is a Source.
+type Source interface {
+	GetAllStates(time time.Time, icao24 string) (goflight.StatesResponse, error)
+}
+
+// Gap is a period without snapshots
+type Gap struct {
+	From time.Time // Time of the last snapshot before the gap
+	To   time.Time // Time of the first snapshot after the gap
+}
+
+// Duration returns the length of the gap
+func (g Gap) Duration() time.Duration {
+	return g.To.Sub(g.From)
+}
+
+// Recorder polls a Source and writes every new snapshot to a Sink
+type Recorder struct {
+	Source     Source          // Source of the snapshots.
+	Sink       Sink            // Sink the snapshots are written to. It is not closed by the recorder.
+	Interval   time.Duration   // Polling interval, DefaultInterval when zero.
+	MaxGap     time.Duration   // Snapshots further apart than MaxGap are reported as a gap, twice the interval when zero.
+	Checkpoint string          // Path of the checkpoint file, no checkpoint is kept when empty.
+	OnGap      func(gap Gap)   // Called for every gap, can be nil.
+	OnError    func(err error) // Called when polling the source fails, can be nil. The recorder keeps polling.
+
+	last int64
+}
+
+type checkpoint struct {
+	Time int64 `json:"time"`
+}
+
+// New creates a Recorder which writes the snapshots of source to sink
+func New(source Source, sink Sink) *Recorder {
+	return &Recorder{Source: source, Sink: sink}
+}
+
+// Run polls the source until the context is done, it returns the error of the context or the first error of
+// the sink or checkpoint
+func (r *Recorder) Run(ctx context.Context) error {
+	if err := r.loadCheckpoint(); err != nil {
+		return err
+	}
+
+	interval := r.Interval
+
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// The context is checked first, select picks randomly when the ticker fired as well
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := r.poll(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches a snapshot and records it, errors of the source are passed to OnError
+func (r *Recorder) poll() error {
+	snapshot, err := r.Source.GetAllStates(time.Time{}, "")
+
+	if err != nil {
+		if r.OnError != nil {
+			r.OnError(err)
+		}
+
+		return nil
+	}
+
+	return r.record(snapshot)
+}
+
+func (r *Recorder) record(snapshot goflight.StatesResponse) error {
+	if snapshot.Time <= r.last {
+		return nil
+	}
+
+	if r.last != 0 && r.OnGap != nil && time.Duration(snapshot.Time-r.last)*time.Second > r.maxGap() {
+		r.OnGap(Gap{From: time.Unix(r.last, 0), To: time.Unix(snapshot.Time, 0)})
+	}
+
+	if err := r.Sink.Write(snapshot); err != nil {
+		return err
+	}
+
+	r.last = snapshot.Time
+
+	return r.saveCheckpoint()
+}
+
+func (r *Recorder) maxGap() time.Duration {
+	if r.MaxGap > 0 {
+		return r.MaxGap
+	}
+
+	if r.Interval > 0 {
+		return 2 * r.Interval
+	}
+
+	return 2 * DefaultInterval
+}
+
+func (r *Recorder) loadCheckpoint() error {
+	if r.Checkpoint == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(r.Checkpoint)
+
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var c checkpoint
+
+	if err = json.Unmarshal(data, &c); err != nil {
+		return err
+	}
+
+	r.last = c.Time
+
+	return nil
+}
+
+// saveCheckpoint replaces the checkpoint file through a rename, so it is never left half written
+func (r *Recorder) saveCheckpoint() error {
+	if r.Checkpoint == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(checkpoint{Time: r.last})
+
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(r.Checkpoint), filepath.Base(r.Checkpoint)+".*")
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), r.Checkpoint)
+}
@@ -0,0 +1,146 @@
+package recorder_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"github.com/marcelblijleven/goflight/recorder"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// scriptedSource returns the snapshots in order and cancels the context after the last one
+type scriptedSource struct {
+	times  []int64
+	errs   map[int]error
+	calls  int
+	cancel context.CancelFunc
+}
+
+func (s *scriptedSource) GetAllStates(_ time.Time, _ string) (goflight.StatesResponse, error) {
+	i := s.calls
+	s.calls++
+
+	if i >= len(s.times)-1 {
+		s.cancel()
+	}
+
+	if i >= len(s.times) {
+		return goflight.StatesResponse{}, errors.New("no more snapshots")
+	}
+
+	if err := s.errs[i]; err != nil {
+		return goflight.StatesResponse{}, err
+	}
+
+	return goflight.StatesResponse{Time: s.times[i], States: []goflight.StateVector{{ICAO24: "484ac1"}}}, nil
+}
+
+func readTimes(t *testing.T, r io.Reader) []int64 {
+	reader, err := recorder.NewReader(r)
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var times []int64
+
+	for {
+		snapshot, err := reader.Read()
+
+		if err == io.EOF {
+			return times
+		}
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		times = append(times, snapshot.Time)
+	}
+}
+
+// record runs a recorder until the source has returned all snapshots
+func record(t *testing.T, r *recorder.Recorder, times []int64, errs map[int]error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.Source = &scriptedSource{times: times, errs: errs, cancel: cancel}
+	r.Interval = time.Millisecond
+
+	if err := r.Run(ctx); err != context.Canceled {
+		t.Fatalf("expected error %v to equal %v", err, context.Canceled)
+	}
+}
+
+func TestRecorder_Run(t *testing.T) {
+	var buf bytes.Buffer
+	var gaps []recorder.Gap
+	var errs []error
+	r := recorder.New(nil, recorder.NewWriterSink(&buf, false))
+	r.MaxGap = 15 * time.Second
+	r.OnGap = func(gap recorder.Gap) { gaps = append(gaps, gap) }
+	r.OnError = func(err error) { errs = append(errs, err) }
+
+	record(t, r, []int64{100, 100, 110, 120, 0, 150, 160}, map[int]error{4: goflight.ErrUnauthorizedAccess})
+
+	if times := readTimes(t, &buf); !reflect.DeepEqual(times, []int64{100, 110, 120, 150, 160}) {
+		t.Errorf("unexpected snapshot times: %v", times)
+	}
+
+	if len(gaps) != 1 || gaps[0].From.Unix() != 120 || gaps[0].To.Unix() != 150 || gaps[0].Duration() != 30*time.Second {
+		t.Errorf("unexpected gaps: %v", gaps)
+	}
+
+	if len(errs) != 1 || errs[0] != goflight.ErrUnauthorizedAccess {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestRecorder_Checkpoint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recorder")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defer os.RemoveAll(dir)
+
+	var buf bytes.Buffer
+	var gaps []recorder.Gap
+	checkpoint := filepath.Join(dir, "checkpoint.json")
+
+	for _, times := range [][]int64{{100, 110}, {110, 150, 160}} {
+		r := recorder.New(nil, recorder.NewWriterSink(&buf, false))
+		r.Checkpoint = checkpoint
+		r.MaxGap = 15 * time.Second
+		r.OnGap = func(gap recorder.Gap) { gaps = append(gaps, gap) }
+		record(t, r, times, nil)
+	}
+
+	if times := readTimes(t, &buf); !reflect.DeepEqual(times, []int64{100, 110, 150, 160}) {
+		t.Errorf("unexpected snapshot times: %v", times)
+	}
+
+	if len(gaps) != 1 || gaps[0].From.Unix() != 110 || gaps[0].To.Unix() != 150 {
+		t.Errorf("unexpected gaps: %v", gaps)
+	}
+
+	if data, _ := ioutil.ReadFile(checkpoint); string(data) != `{"time":160}` {
+		t.Errorf("unexpected checkpoint: %s", data)
+	}
+}
+
+func TestClient_Source(t *testing.T) {
+	client, err := goflight.NewClient("", "", nil)
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var _ recorder.Source = client.States
+}
@@ -0,0 +1,306 @@
+package recorder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"github.com/marcelblijleven/goflight/storage"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Sink receives the snapshots of a Recorder
+type Sink interface {
+	Write(snapshot goflight.StatesResponse) error
+	Close() error
+}
+
+// WriterSink writes the snapshots as newline delimited JSON to an io.Writer, optionally gzip compressed.
+// The compressed stream is flushed after every snapshot, Close must be called to complete it.
+type WriterSink struct {
+	w       io.Writer
+	gzip    *gzip.Writer
+	encoder *json.Encoder
+}
+
+// NewWriterSink creates a WriterSink, the snapshots are gzip compressed when compress is true
+func NewWriterSink(w io.Writer, compress bool) *WriterSink {
+	s := &WriterSink{w: w}
+
+	if compress {
+		s.gzip = gzip.NewWriter(w)
+		w = s.gzip
+	}
+
+	s.encoder = json.NewEncoder(w)
+
+	return s
+}
+
+// Write writes the snapshot as a single line
+func (s *WriterSink) Write(snapshot goflight.StatesResponse) error {
+	if err := s.encoder.Encode(snapshot); err != nil {
+		return err
+	}
+
+	if s.gzip != nil {
+		return s.gzip.Flush()
+	}
+
+	return nil
+}
+
+// Close completes the compressed stream, the underlying writer is not closed
+func (s *WriterSink) Close() error {
+	if s.gzip != nil {
+		return s.gzip.Close()
+	}
+
+	return nil
+}
+
+// FileSink writes the snapshots to a newline delimited JSON file per hour, named after the hour of the snapshot
+// time in UTC, e.g. states-2020-04-04T20.ndjson.gz. Files which already exist are appended to, so a restarted
+// recorder continues in the file of the current hour.
+//
+// A file which wasn't closed, e.g. because the recorder crashed, ends with an incomplete gzip member or line.
+// Appending to it would make the rest of the file unreadable, so the snapshots are written to the next file of
+// the hour instead, e.g. states-2020-04-04T20_1.ndjson.gz. FileNames returns the files of an hour in order.
+type FileSink struct {
+	dir      string
+	compress bool
+	hour     time.Time
+	file     *os.File
+	buffer   *bufio.Writer
+	sink     *WriterSink
+}
+
+// NewFileSink creates a FileSink which writes to dir, the files are gzip compressed when compress is true
+func NewFileSink(dir string, compress bool) *FileSink {
+	return &FileSink{dir: dir, compress: compress}
+}
+
+// Write writes the snapshot to the file of its hour, the previous file is closed when the hour changes
+func (s *FileSink) Write(snapshot goflight.StatesResponse) error {
+	hour := time.Unix(snapshot.Time, 0).UTC().Truncate(time.Hour)
+
+	if s.file == nil || !hour.Equal(s.hour) {
+		if err := s.open(hour); err != nil {
+			return err
+		}
+	}
+
+	if err := s.sink.Write(snapshot); err != nil {
+		return err
+	}
+
+	return s.buffer.Flush()
+}
+
+// FileName returns the name of the first file snapshots of the hour are written to
+func (s *FileSink) FileName(hour time.Time) string {
+	return s.fileName(hour, 0)
+}
+
+// FileNames returns the names of the existing files of the hour, in the order they were written
+func (s *FileSink) FileNames(hour time.Time) ([]string, error) {
+	var names []string
+
+	for run := 0; ; run++ {
+		name := s.fileName(hour, run)
+
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			return names, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		names = append(names, name)
+	}
+}
+
+// fileName returns the name of a file of the hour, run is the number of earlier files which weren't closed
+func (s *FileSink) fileName(hour time.Time, run int) string {
+	name := "states-" + hour.UTC().Format("2006-01-02T15")
+
+	if run > 0 {
+		name += "_" + strconv.Itoa(run)
+	}
+
+	name += ".ndjson"
+
+	if s.compress {
+		name += ".gz"
+	}
+
+	return filepath.Join(s.dir, name)
+}
+
+// nextFileName returns the first file of the hour which doesn't exist yet or was closed
+func (s *FileSink) nextFileName(hour time.Time) (string, error) {
+	for run := 0; ; run++ {
+		name := s.fileName(hour, run)
+		ok, err := s.complete(name)
+
+		if err != nil {
+			return "", err
+		}
+
+		if ok {
+			return name, nil
+		}
+	}
+}
+
+// complete reports whether the file doesn't exist or can be appended to, which is the case when it ends with
+// a complete gzip member or line
+func (s *FileSink) complete(name string) (bool, error) {
+	f, err := os.Open(name)
+
+	if os.IsNotExist(err) {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	defer f.Close()
+
+	if s.compress {
+		gz, err := gzip.NewReader(bufio.NewReader(f))
+
+		if err == io.EOF {
+			return true, nil
+		}
+
+		if err != nil {
+			return false, nil
+		}
+
+		_, err = io.Copy(ioutil.Discard, gz)
+
+		return err == nil, nil
+	}
+
+	info, err := f.Stat()
+
+	if err != nil || info.Size() == 0 {
+		return err == nil, err
+	}
+
+	last := make([]byte, 1)
+
+	if _, err = f.ReadAt(last, info.Size()-1); err != nil {
+		return false, err
+	}
+
+	return last[0] == '\n', nil
+}
+
+func (s *FileSink) open(hour time.Time) error {
+	if err := s.Close(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+
+	name, err := s.nextFileName(hour)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	// Appending to a closed compressed file adds a gzip member, which gzip readers read as a single stream
+	s.hour = hour
+	s.file = f
+	s.buffer = bufio.NewWriter(f)
+	s.sink = NewWriterSink(s.buffer, s.compress)
+
+	return nil
+}
+
+// Close closes the current file
+func (s *FileSink) Close() error {
+	if s.file == nil {
+		return nil
+	}
+
+	f := s.file
+	s.file = nil
+	err := s.sink.Close()
+
+	if flushErr := s.buffer.Flush(); err == nil {
+		err = flushErr
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// StoreSink saves the snapshots in a storage.Store
+type StoreSink struct {
+	store storage.Store
+}
+
+// NewStoreSink creates a StoreSink
+func NewStoreSink(store storage.Store) *StoreSink {
+	return &StoreSink{store: store}
+}
+
+// Write saves the snapshot
+func (s *StoreSink) Write(snapshot goflight.StatesResponse) error {
+	return s.store.SaveSnapshot(snapshot)
+}
+
+// Close closes the store
+func (s *StoreSink) Close() error {
+	return s.store.Close()
+}
+
+// Reader reads snapshots written by a WriterSink or FileSink. Gzip compression is detected automatically.
+type Reader struct {
+	decoder *json.Decoder
+}
+
+// NewReader creates a Reader
+func NewReader(r io.Reader) (*Reader, error) {
+	buffered := bufio.NewReader(r)
+	header, _ := buffered.Peek(2)
+
+	if len(header) == 2 && header[0] == 0x1f && header[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &Reader{decoder: json.NewDecoder(gz)}, nil
+	}
+
+	return &Reader{decoder: json.NewDecoder(buffered)}, nil
+}
+
+// Read returns the next snapshot, or io.EOF when there are no more snapshots
+func (r *Reader) Read() (goflight.StatesResponse, error) {
+	var snapshot goflight.StatesResponse
+	err := r.decoder.Decode(&snapshot)
+
+	return snapshot, err
+}
@@ -0,0 +1,216 @@
+package recorder_test
+
+import (
+	"bytes"
+	"github.com/marcelblijleven/goflight"
+	"github.com/marcelblijleven/goflight/recorder"
+	"github.com/marcelblijleven/goflight/storage"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWriterSink_Compressed(t *testing.T) {
+	var buf bytes.Buffer
+	sink := recorder.NewWriterSink(&buf, true)
+
+	for _, s := range []int64{100, 110} {
+		if err := sink.Write(goflight.StatesResponse{Time: s}); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if !bytes.HasPrefix(buf.Bytes(), []byte{0x1f, 0x8b}) {
+		t.Errorf("expected output to be gzip compressed")
+	}
+
+	if times := readTimes(t, &buf); !reflect.DeepEqual(times, []int64{100, 110}) {
+		t.Errorf("unexpected snapshot times: %v", times)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "recorder")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defer os.RemoveAll(dir)
+
+	// 1586041200 is 2020-04-04T23:00:00Z, the third snapshot is written after reopening the sink
+	for _, times := range [][]int64{{1586041190, 1586041200}, {1586041210}} {
+		sink := recorder.NewFileSink(dir, true)
+
+		for _, s := range times {
+			if err := sink.Write(goflight.StatesResponse{Time: s}); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+
+		if err := sink.Close(); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	sink := recorder.NewFileSink(dir, true)
+	expected := map[string][]int64{
+		sink.FileName(time.Unix(1586037600, 0)): {1586041190},
+		sink.FileName(time.Unix(1586041200, 0)): {1586041200, 1586041210},
+	}
+
+	if name := filepath.Base(sink.FileName(time.Unix(1586041200, 0))); name != "states-2020-04-04T23.ndjson.gz" {
+		t.Errorf("unexpected file name: %v", name)
+	}
+
+	for name, times := range expected {
+		f, err := os.Open(name)
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if actual := readTimes(t, f); !reflect.DeepEqual(actual, times) {
+			t.Errorf("expected snapshot times %v in %v to equal %v", actual, name, times)
+		}
+
+		f.Close()
+	}
+}
+
+// readAbandoned reads the snapshots of a file which wasn't closed, up to the error at its end
+func readAbandoned(t *testing.T, name string) []int64 {
+	f, err := os.Open(name)
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defer f.Close()
+	reader, err := recorder.NewReader(f)
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var times []int64
+
+	for {
+		snapshot, err := reader.Read()
+
+		if err == io.EOF {
+			t.Fatal("expected the file to be incomplete")
+		}
+
+		if err != nil {
+			return times
+		}
+
+		times = append(times, snapshot.Time)
+	}
+}
+
+func TestFileSink_Abandoned(t *testing.T) {
+	for _, compress := range []bool{true, false} {
+		dir, err := ioutil.TempDir("", "recorder")
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		defer os.RemoveAll(dir)
+
+		// The first sink crashes without Close, after two snapshots
+		abandoned := recorder.NewFileSink(dir, compress)
+
+		for _, s := range []int64{1586041200, 1586041210} {
+			if err := abandoned.Write(goflight.StatesResponse{Time: s}); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+
+		hour := time.Unix(1586041200, 0)
+
+		if !compress {
+			// Simulate a crash halfway through writing a line
+			f, err := os.OpenFile(abandoned.FileName(hour), os.O_WRONLY|os.O_APPEND, 0644)
+
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			f.WriteString(`{"time":15860`)
+			f.Close()
+		}
+
+		sink := recorder.NewFileSink(dir, compress)
+
+		for _, s := range []int64{1586041220, 1586041230} {
+			if err := sink.Write(goflight.StatesResponse{Time: s}); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+
+		if err := sink.Close(); err != nil {
+			t.Fatal(err.Error())
+		}
+
+		names, err := sink.FileNames(hour)
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		expected := []string{filepath.Join(dir, "states-2020-04-04T23.ndjson"), filepath.Join(dir, "states-2020-04-04T23_1.ndjson")}
+
+		if compress {
+			expected = []string{expected[0] + ".gz", expected[1] + ".gz"}
+		}
+
+		if !reflect.DeepEqual(names, expected) {
+			t.Fatalf("expected file names %v to equal %v", names, expected)
+		}
+
+		if times := readAbandoned(t, names[0]); !reflect.DeepEqual(times, []int64{1586041200, 1586041210}) {
+			t.Errorf("expected the snapshots before the crash to be readable, got %v", times)
+		}
+
+		f, err := os.Open(names[1])
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if times := readTimes(t, f); !reflect.DeepEqual(times, []int64{1586041220, 1586041230}) {
+			t.Errorf("expected the snapshots after the restart in a new file, got %v", times)
+		}
+
+		f.Close()
+	}
+}
+
+func TestStoreSink(t *testing.T) {
+	store := storage.NewMemoryStore()
+	sink := recorder.NewStoreSink(store)
+	snapshot := goflight.StatesResponse{Time: 100, States: []goflight.StateVector{{ICAO24: "484ac1"}}}
+
+	if err := sink.Write(snapshot); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if stored, err := store.Snapshot(time.Unix(100, 0)); err != nil || !reflect.DeepEqual(stored, snapshot) {
+		t.Errorf("expected %+v to equal %+v", stored, snapshot)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Error(err.Error())
+	}
+}
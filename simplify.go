@@ -0,0 +1,322 @@
+package goflight
+
+import (
+	"container/heap"
+	"math"
+	"time"
+)
+
+// The simplification and resampling functions drop waypoints without position. The first and last waypoint and
+// the waypoints on both sides of a change of OnGround, the takeoff and landing points, are always kept.
+
+// SimplifyDouglasPeucker simplifies the path with the Douglas-Peucker algorithm, removing waypoints which are
+// within tolerance meters of the line between the waypoints that are kept. The altitude is taken into account
+// when every waypoint has a barometric altitude.
+func SimplifyDouglasPeucker(path []Waypoint, tolerance float64) []Waypoint {
+	return simplifyPath(path, func(points []vector, keep []bool, first, last int) {
+		douglasPeucker(points, keep, first, last, tolerance)
+	})
+}
+
+// SimplifyVisvalingam simplifies the path with the Visvalingam-Whyatt algorithm, repeatedly removing the waypoint
+// which forms the triangle with the smallest area with its neighbours, until every triangle is at least
+// tolerance² square meters. The altitude is taken into account when every waypoint has a barometric altitude.
+func SimplifyVisvalingam(path []Waypoint, tolerance float64) []Waypoint {
+	return simplifyPath(path, func(points []vector, keep []bool, first, last int) {
+		visvalingam(points, keep, first, last, tolerance*tolerance)
+	})
+}
+
+// vector is a position in meters in a local plane, with the altitude as third dimension
+type vector [3]float64
+
+func (v vector) sub(o vector) vector {
+	return vector{v[0] - o[0], v[1] - o[1], v[2] - o[2]}
+}
+
+func (v vector) dot(o vector) float64 {
+	return v[0]*o[0] + v[1]*o[1] + v[2]*o[2]
+}
+
+func (v vector) cross(o vector) vector {
+	return vector{v[1]*o[2] - v[2]*o[1], v[2]*o[0] - v[0]*o[2], v[0]*o[1] - v[1]*o[0]}
+}
+
+func (v vector) length() float64 {
+	return math.Sqrt(v.dot(v))
+}
+
+// positioned returns the waypoints which have a position
+func positioned(path []Waypoint) []Waypoint {
+	result := make([]Waypoint, 0, len(path))
+
+	for _, w := range path {
+		if _, ok := w.Position(); ok {
+			result = append(result, w)
+		}
+	}
+
+	return result
+}
+
+// fixedWaypoints marks the first and last waypoint and the waypoints around takeoffs and landings
+func fixedWaypoints(path []Waypoint) []bool {
+	keep := make([]bool, len(path))
+
+	if len(path) == 0 {
+		return keep
+	}
+
+	keep[0], keep[len(path)-1] = true, true
+
+	for i := 1; i < len(path); i++ {
+		if path[i].OnGround != path[i-1].OnGround {
+			keep[i-1], keep[i] = true, true
+		}
+	}
+
+	return keep
+}
+
+// simplifyPath runs the simplification between every pair of consecutive fixed waypoints. The positions are
+// projected on a plane tangent at the first waypoint, which is accurate enough for the tolerances used.
+func simplifyPath(path []Waypoint, simplify func(points []vector, keep []bool, first, last int)) []Waypoint {
+	path = positioned(path)
+	keep := fixedWaypoints(path)
+
+	if len(path) < 3 {
+		return path
+	}
+
+	useAltitude := true
+
+	for _, w := range path {
+		if w.BaroAltitude == nil {
+			useAltitude = false
+		}
+	}
+
+	origin, _ := path[0].Position()
+	points := make([]vector, len(path))
+
+	for i, w := range path {
+		p, _ := w.Position()
+		points[i][0], points[i][1] = localOffset(origin, p)
+
+		if useAltitude {
+			points[i][2] = *w.BaroAltitude
+		}
+	}
+
+	first := 0
+
+	for i := 1; i < len(path); i++ {
+		if keep[i] {
+			simplify(points, keep, first, i)
+			first = i
+		}
+	}
+
+	result := make([]Waypoint, 0, len(path))
+
+	for i, w := range path {
+		if keep[i] {
+			result = append(result, w)
+		}
+	}
+
+	return result
+}
+
+// localOffset returns the east and north offset in meters of p from origin
+func localOffset(origin, p Point) (east, north float64) {
+	longitude := math.Remainder(p.Longitude-origin.Longitude, 360)
+	east = radians(longitude) * earthRadius * math.Cos(radians((origin.Latitude+p.Latitude)/2))
+	north = radians(p.Latitude-origin.Latitude) * earthRadius
+
+	return east, north
+}
+
+// segmentDistance returns the distance of p to the line segment from a to b
+func segmentDistance(p, a, b vector) float64 {
+	ab, ap := b.sub(a), p.sub(a)
+	length := ab.dot(ab)
+
+	if length == 0 {
+		return ap.length()
+	}
+
+	t := math.Max(0, math.Min(1, ap.dot(ab)/length))
+
+	return ap.sub(vector{ab[0] * t, ab[1] * t, ab[2] * t}).length()
+}
+
+func douglasPeucker(points []vector, keep []bool, first, last int, tolerance float64) {
+	type span struct{ first, last int }
+	stack := []span{{first, last}}
+
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		index, max := -1, tolerance
+
+		for i := s.first + 1; i < s.last; i++ {
+			if d := segmentDistance(points[i], points[s.first], points[s.last]); d > max {
+				index, max = i, d
+			}
+		}
+
+		if index >= 0 {
+			keep[index] = true
+			stack = append(stack, span{s.first, index}, span{index, s.last})
+		}
+	}
+}
+
+// triangle is a waypoint in the Visvalingam heap, with the area of the triangle it forms with its neighbours
+type triangle struct {
+	index      int
+	prev, next int
+	area       float64
+	heapIndex  int
+}
+
+type triangleHeap []*triangle
+
+func (h triangleHeap) Len() int           { return len(h) }
+func (h triangleHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+
+func (h triangleHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex, h[j].heapIndex = i, j
+}
+
+func (h *triangleHeap) Push(x interface{}) {
+	t := x.(*triangle)
+	t.heapIndex = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *triangleHeap) Pop() interface{} {
+	old := *h
+	t := old[len(old)-1]
+	*h = old[:len(old)-1]
+
+	return t
+}
+
+func visvalingam(points []vector, keep []bool, first, last int, minArea float64) {
+	if last-first < 2 {
+		return
+	}
+
+	area := func(t *triangle) float64 {
+		a, b, c := points[t.prev], points[t.index], points[t.next]
+		return b.sub(a).cross(c.sub(a)).length() / 2
+	}
+
+	triangles := make(map[int]*triangle, last-first-1)
+	h := make(triangleHeap, 0, last-first-1)
+
+	for i := first + 1; i < last; i++ {
+		t := &triangle{index: i, prev: i - 1, next: i + 1}
+		t.area = area(t)
+		triangles[i] = t
+		heap.Push(&h, t)
+	}
+
+	for h.Len() > 0 {
+		t := heap.Pop(&h).(*triangle)
+
+		if t.area >= minArea {
+			keep[t.index] = true
+
+			for _, remaining := range h {
+				keep[remaining.index] = true
+			}
+
+			return
+		}
+
+		// Removing the waypoint changes the triangles of its neighbours, their area can't become smaller than
+		// the area removed, so points are removed in order of significance
+		if prev, ok := triangles[t.prev]; ok {
+			prev.next = t.next
+			prev.area = math.Max(area(prev), t.area)
+			heap.Fix(&h, prev.heapIndex)
+		}
+
+		if next, ok := triangles[t.next]; ok {
+			next.prev = t.prev
+			next.area = math.Max(area(next), t.area)
+			heap.Fix(&h, next.heapIndex)
+		}
+
+		delete(triangles, t.index)
+	}
+}
+
+// ResamplePath returns waypoints at a fixed interval from the first waypoint, with the position, altitude and
+// track linearly interpolated between the surrounding waypoints. The last waypoint and the takeoff and landing
+// points are added to the result as well.
+func ResamplePath(path []Waypoint, interval time.Duration) []Waypoint {
+	path = positioned(path)
+	step := int64(interval / time.Second)
+
+	if len(path) < 2 || step <= 0 {
+		return path
+	}
+
+	keep := fixedWaypoints(path)
+	result := make([]Waypoint, 0, int((path[len(path)-1].Time-path[0].Time)/step)+len(path))
+	i := 0
+
+	for t := path[0].Time; ; t += step {
+		// Add the fixed waypoints before t
+		for ; i < len(path) && path[i].Time <= t; i++ {
+			if keep[i] && path[i].Time < t {
+				result = append(result, path[i])
+			}
+		}
+
+		if t > path[len(path)-1].Time {
+			break
+		}
+
+		if path[i-1].Time == t {
+			result = append(result, path[i-1])
+			continue
+		}
+
+		result = append(result, interpolateWaypoint(path[i-1], path[i], t))
+	}
+
+	return result
+}
+
+// interpolateWaypoint returns the waypoint at time t between a and b, OnGround is taken from a
+func interpolateWaypoint(a, b Waypoint, t int64) Waypoint {
+	f := float64(t-a.Time) / float64(b.Time-a.Time)
+	latitude := *a.Latitude + (*b.Latitude-*a.Latitude)*f
+	longitude := *a.Longitude + math.Remainder(*b.Longitude-*a.Longitude, 360)*f
+
+	if longitude > 180 {
+		longitude -= 360
+	} else if longitude < -180 {
+		longitude += 360
+	}
+
+	w := Waypoint{Time: t, Latitude: &latitude, Longitude: &longitude, OnGround: a.OnGround}
+
+	if a.BaroAltitude != nil && b.BaroAltitude != nil {
+		altitude := *a.BaroAltitude + (*b.BaroAltitude-*a.BaroAltitude)*f
+		w.BaroAltitude = &altitude
+	}
+
+	if a.TrueTrack != nil && b.TrueTrack != nil {
+		track := math.Mod(*a.TrueTrack+math.Remainder(*b.TrueTrack-*a.TrueTrack, 360)*f+360, 360)
+		w.TrueTrack = &track
+	}
+
+	return w
+}
@@ -0,0 +1,150 @@
+package goflight_test
+
+import (
+	"github.com/marcelblijleven/goflight"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// eastbound returns a waypoint at time t, t meters east of 52N 4E with an offset of north meters to the north
+func eastbound(t int64, north float64, onGround bool) goflight.Waypoint {
+	p := goflight.Destination(goflight.Point{Latitude: 52, Longitude: 4}, 90, float64(t))
+	p = goflight.Destination(p, 0, north)
+
+	return goflight.Waypoint{Time: t, Latitude: floatPtr(p.Latitude), Longitude: floatPtr(p.Longitude), OnGround: onGround}
+}
+
+func waypointTimes(path []goflight.Waypoint) []int64 {
+	times := make([]int64, len(path))
+
+	for i, w := range path {
+		times[i] = w.Time
+	}
+
+	return times
+}
+
+var simplifyTests = []struct {
+	name     string
+	path     []goflight.Waypoint
+	expected []int64
+}{
+	{
+		"straight line with noise",
+		[]goflight.Waypoint{eastbound(0, 0, false), eastbound(1000, 5, false), eastbound(2000, -5, false), eastbound(3000, 0, false)},
+		[]int64{0, 3000},
+	},
+	{
+		"corner",
+		[]goflight.Waypoint{eastbound(0, 0, false), eastbound(1000, 0, false), eastbound(2000, 1000, false), eastbound(3000, 2000, false)},
+		[]int64{0, 1000, 3000},
+	},
+	{
+		"takeoff on a straight line",
+		[]goflight.Waypoint{eastbound(0, 0, true), eastbound(1000, 0, true), eastbound(2000, 0, true), eastbound(3000, 0, false), eastbound(4000, 0, false)},
+		[]int64{0, 2000, 3000, 4000},
+	},
+	{
+		"missing position",
+		[]goflight.Waypoint{eastbound(0, 0, false), {Time: 500}, eastbound(1000, 0, false)},
+		[]int64{0, 1000},
+	},
+}
+
+func TestSimplify(t *testing.T) {
+	// The Visvalingam tolerance is the square root of an area, so it needs a larger value for the same result
+	simplifiers := []struct {
+		name      string
+		simplify  func([]goflight.Waypoint, float64) []goflight.Waypoint
+		tolerance float64
+	}{
+		{"DouglasPeucker", goflight.SimplifyDouglasPeucker, 50},
+		{"Visvalingam", goflight.SimplifyVisvalingam, 200},
+	}
+
+	for _, s := range simplifiers {
+		for _, tt := range simplifyTests {
+			t.Run(s.name+"/"+tt.name, func(t *testing.T) {
+				if times := waypointTimes(s.simplify(tt.path, s.tolerance)); !reflect.DeepEqual(times, tt.expected) {
+					t.Errorf("expected %v to equal %v", times, tt.expected)
+				}
+			})
+		}
+	}
+}
+
+func TestSimplify_Altitude(t *testing.T) {
+	path := []goflight.Waypoint{eastbound(0, 0, false), eastbound(1000, 0, false), eastbound(2000, 0, false)}
+
+	for i, altitude := range []float64{1000, 1500, 1000} {
+		path[i].BaroAltitude = floatPtr(altitude)
+	}
+
+	if simplified := goflight.SimplifyDouglasPeucker(path, 100); len(simplified) != 3 {
+		t.Errorf("expected the climb and descent to be kept: %v", waypointTimes(simplified))
+	}
+
+	path[1].BaroAltitude = nil
+
+	if simplified := goflight.SimplifyDouglasPeucker(path, 100); len(simplified) != 2 {
+		t.Errorf("expected the altitude to be ignored: %v", waypointTimes(simplified))
+	}
+}
+
+func TestSimplify_MockTrack(t *testing.T) {
+	path := readMockTrack(t).Path
+
+	// Every remaining waypoint is either the first, last or a takeoff point
+	for _, simplified := range [][]goflight.Waypoint{
+		goflight.SimplifyDouglasPeucker(path, 1e6),
+		goflight.SimplifyVisvalingam(path, 1e6),
+	} {
+		if times := waypointTimes(simplified); !reflect.DeepEqual(times, []int64{1586030400, 1586030460, 1586030520, 1586031309}) {
+			t.Errorf("unexpected waypoints: %v", times)
+		}
+	}
+}
+
+func TestResamplePath(t *testing.T) {
+	path := []goflight.Waypoint{eastbound(0, 0, true), eastbound(25, 0, false), eastbound(45, 0, false)}
+	path[1].BaroAltitude, path[2].BaroAltitude = floatPtr(100), floatPtr(300)
+	path[1].TrueTrack, path[2].TrueTrack = floatPtr(350), floatPtr(20)
+	resampled := goflight.ResamplePath(path, 10*time.Second)
+
+	if times := waypointTimes(resampled); !reflect.DeepEqual(times, []int64{0, 10, 20, 25, 30, 40, 45}) {
+		t.Fatalf("unexpected waypoint times: %v", times)
+	}
+
+	if !resampled[2].OnGround || resampled[3].OnGround || resampled[2].BaroAltitude != nil {
+		t.Errorf("unexpected waypoint before takeoff: %+v", resampled[2])
+	}
+
+	w := resampled[5]
+	expected := eastbound(40, 0, false)
+
+	if math.Abs(*w.Longitude-*expected.Longitude) > 1e-6 || math.Abs(*w.Latitude-*expected.Latitude) > 1e-6 {
+		t.Errorf("expected position %v, %v to equal %v, %v", *w.Latitude, *w.Longitude, *expected.Latitude, *expected.Longitude)
+	}
+
+	if *w.BaroAltitude != 250 || math.Abs(*w.TrueTrack-12.5) > 1e-9 {
+		t.Errorf("unexpected altitude %v or track %v", *w.BaroAltitude, *w.TrueTrack)
+	}
+}
+
+func TestResamplePath_Antimeridian(t *testing.T) {
+	path := []goflight.Waypoint{
+		{Time: 0, Latitude: floatPtr(0), Longitude: floatPtr(179)},
+		{Time: 40, Latitude: floatPtr(0), Longitude: floatPtr(-179)},
+	}
+
+	resampled := goflight.ResamplePath(path, 10*time.Second)
+	expected := []float64{179, 179.5, -180, -179.5, -179}
+
+	for i, w := range resampled {
+		if math.Abs(*w.Longitude-expected[i]) > 1e-9 && math.Abs(math.Abs(*w.Longitude)-180) > 1e-9 {
+			t.Errorf("expected longitude %v to equal %v", *w.Longitude, expected[i])
+		}
+	}
+}
@@ -34,6 +34,15 @@ type StateVector struct {
 	Squawk         *string  // The transponder code aka Squawk. Can be nil.
 	Spi            *bool    // Whether flight status indicates special purpose indicator. Can be nil
 	PositionSource int      // Origin of this state’s position: 0 = ADS-B, 1 = ASTERIX, 2 = MLAT
+	Category       *int     // Aircraft category. Only received when the extended request parameter is used, nil otherwise.
+}
+
+// BoundingBox is an area in WGS-84 coordinates, states can be requested for the aircraft within it
+type BoundingBox struct {
+	LaMin float64 // Lower bound for the latitude in decimal degrees.
+	LoMin float64 // Lower bound for the longitude in decimal degrees.
+	LaMax float64 // Upper bound for the latitude in decimal degrees.
+	LoMax float64 // Upper bound for the longitude in decimal degrees.
 }
 
 // StatesResponse is the response retrieved from the /api/states/all and /api/states/own endpoints
@@ -44,6 +53,16 @@ type StatesResponse struct {
 
 // UnmarshalJSON unmarshals the provided []byte onto a StateVector struct
 func (s *StateVector) UnmarshalJSON(buf []byte) error {
+	// The fast decoder handles the common form of the array, anything else is left to encoding/json
+	if decodeStateVector(buf, s) {
+		return nil
+	}
+
+	return s.unmarshalReflect(buf)
+}
+
+// unmarshalReflect unmarshals the positional array using encoding/json
+func (s *StateVector) unmarshalReflect(buf []byte) error {
 	tmp := []interface{}{
 		&s.ICAO24,
 		&s.Callsign,
@@ -62,22 +81,61 @@ func (s *StateVector) UnmarshalJSON(buf []byte) error {
 		&s.Squawk,
 		&s.Spi,
 		&s.PositionSource,
+		&s.Category,
 	}
 
-	expectedLen := len(tmp)
+	// The category is optional, so the array has either 17 or 18 elements
+	s.Category = nil
+	var elements []json.RawMessage
 
-	if err := json.Unmarshal(buf, &tmp); err != nil {
+	if err := json.Unmarshal(buf, &elements); err != nil {
 		return err
 	}
 
-	if actual, expected := len(tmp), expectedLen; actual != expected {
+	if actual, expected := len(elements), len(tmp); actual != expected && actual != expected-1 {
 		return errors.New("incorrect number of fields in StateVector json")
 	}
 
+	// Decode the elements one by one, so a decoding error can name the field
+	for i, element := range elements {
+		if err := json.Unmarshal(element, tmp[i]); err != nil {
+			return &FieldError{Field: stateVectorColumns[i].name, Err: err}
+		}
+	}
+
 	return nil
 }
 
-func (s *statesService) getStatesRequest(endpoint string, timeParam time.Time, icao24 string) (*http.Request, error) {
+// MarshalJSON marshals the StateVector into the positional array format used by the Opensky API
+func (s StateVector) MarshalJSON() ([]byte, error) {
+	tmp := []interface{}{
+		s.ICAO24,
+		s.Callsign,
+		s.OriginCountry,
+		s.TimePosition,
+		s.LastContact,
+		s.Longitude,
+		s.Latitude,
+		s.BaroAltitude,
+		s.OnGround,
+		s.Velocity,
+		s.TrueTrack,
+		s.VerticalRate,
+		s.Sensors,
+		s.GeoAltitude,
+		s.Squawk,
+		s.Spi,
+		s.PositionSource,
+	}
+
+	if s.Category != nil {
+		tmp = append(tmp, s.Category)
+	}
+
+	return json.Marshal(tmp)
+}
+
+func (s *statesService) getStatesRequest(endpoint string, timeParam time.Time, icao24 string, box *BoundingBox) (*http.Request, error) {
 	method := "GET"
 
 	e, err := url.Parse(endpoint)
@@ -94,26 +152,35 @@ func (s *statesService) getStatesRequest(endpoint string, timeParam time.Time, i
 		return nil, err
 	}
 
+	params := req.URL.Query()
+
 	if timeParam, ok := checkTime(timeParam); ok {
-		req.URL.Query().Add("time", strconv.FormatInt(timeParam.Unix(), 10))
+		params.Add("time", strconv.FormatInt(timeParam.Unix(), 10))
 	}
 
 	if icao24, ok := checkString(icao24); ok {
-		req.URL.Query().Add("icao24", icao24)
+		params.Add("icao24", icao24)
 	}
 
-	req.URL.RawQuery = req.URL.Query().Encode()
+	if box != nil {
+		params.Add("lamin", strconv.FormatFloat(box.LaMin, 'f', -1, 64))
+		params.Add("lomin", strconv.FormatFloat(box.LoMin, 'f', -1, 64))
+		params.Add("lamax", strconv.FormatFloat(box.LaMax, 'f', -1, 64))
+		params.Add("lomax", strconv.FormatFloat(box.LoMax, 'f', -1, 64))
+	}
+
+	req.URL.RawQuery = params.Encode()
 
 	return req, nil
 }
 
-// GetAllStates returns the response of /api/states/all
-func (s *statesService) GetAllStates(time time.Time, icao24 string) (StatesResponse, error) {
+// getAllStatesResponse performs the request to /api/states/all and checks the status of the response
+func (s *statesService) getAllStatesResponse(time time.Time, icao24 string, box *BoundingBox) (*http.Response, error) {
 	endpoint := "/api/states/all"
-	req, err := s.getStatesRequest(endpoint, time, icao24)
+	req, err := s.getStatesRequest(endpoint, time, icao24, box)
 
 	if err != nil {
-		return StatesResponse{}, err
+		return nil, err
 	}
 
 	username, okUser := checkString(s.client.username)
@@ -125,18 +192,41 @@ func (s *statesService) GetAllStates(time time.Time, icao24 string) (StatesRespo
 	}
 
 	resp, err := s.client.httpClient.Do(req)
-	var statesResponse StatesResponse
 
 	if err != nil {
-		return statesResponse, err
+		return nil, err
 	}
 
 	if resp.StatusCode/100 != 2 {
+		resp.Body.Close()
+
 		if resp.StatusCode == 403 {
-			return statesResponse, ErrUnauthorizedAccess
+			return nil, ErrUnauthorizedAccess
 		}
 
-		return statesResponse, fmt.Errorf("%v - %v", resp.StatusCode, resp.Status)
+		return nil, fmt.Errorf("%v - %v", resp.StatusCode, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// GetAllStates returns the response of /api/states/all
+func (s *statesService) GetAllStates(time time.Time, icao24 string) (StatesResponse, error) {
+	return s.getAllStates(time, icao24, nil)
+}
+
+// GetStatesInBoundingBox returns the response of /api/states/all for the aircraft within the bounding box.
+// Opensky charges fewer credits for a request with a small bounding box than for a request of all states.
+func (s *statesService) GetStatesInBoundingBox(time time.Time, box BoundingBox) (StatesResponse, error) {
+	return s.getAllStates(time, "", &box)
+}
+
+func (s *statesService) getAllStates(time time.Time, icao24 string, box *BoundingBox) (StatesResponse, error) {
+	var statesResponse StatesResponse
+	resp, err := s.getAllStatesResponse(time, icao24, box)
+
+	if err != nil {
+		return statesResponse, err
 	}
 
 	defer resp.Body.Close()
@@ -156,7 +246,7 @@ func (s *statesService) GetAllStates(time time.Time, icao24 string) (StatesRespo
 // GetOwnStates returns the response of /api/states/own
 func (s *statesService) GetOwnStates(time time.Time, icao24 string) (*StatesResponse, error) {
 	endpoint := "/api/states/own"
-	req, err := s.getStatesRequest(endpoint, time, icao24)
+	req, err := s.getStatesRequest(endpoint, time, icao24, nil)
 
 	if err != nil {
 		return nil, err
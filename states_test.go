@@ -5,7 +5,11 @@ import (
 	"errors"
 	"github.com/marcelblijleven/goflight"
 	"io/ioutil"
+	"net/http"
 	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -15,6 +19,7 @@ var stateVectorTests = []struct {
 }{
 	{"./mocks/state_vector.json"},
 	{"./mocks/state_vector_null_values.json"},
+	{"./mocks/state_vector_category.json"},
 }
 
 func TestStateVector_UnmarshalJSON(t *testing.T) {
@@ -57,6 +62,86 @@ func TestStateVector_UnmarshalJSON_IncorrectLength(t *testing.T) {
 	}
 }
 
+// roundTripTarget returns a value to decode the mock file onto, based on its name
+func roundTripTarget(name string) interface{} {
+	switch {
+	case strings.HasPrefix(name, "state_vector"):
+		return &goflight.StateVector{}
+	case strings.HasPrefix(name, "states"):
+		return &goflight.StatesResponse{}
+	case strings.HasPrefix(name, "flights"):
+		return &[]goflight.Flight{}
+	case strings.HasPrefix(name, "track"):
+		return &goflight.Track{}
+	}
+
+	return nil
+}
+
+func TestStateVector_MarshalJSON_RoundTrip(t *testing.T) {
+	files, err := filepath.Glob("./mocks/*.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			data, err := ioutil.ReadFile(file)
+
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			name := filepath.Base(file)
+			first := roundTripTarget(name)
+
+			if first == nil {
+				t.Skip("no round trip target for mock file")
+			}
+
+			if name == "state_vector_incorrect_length.json" {
+				t.Skip("mock file is not valid input")
+			}
+
+			if err = json.Unmarshal(data, first); err != nil {
+				t.Fatal(err.Error())
+			}
+
+			encoded, err := json.Marshal(first)
+
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+
+			second := roundTripTarget(filepath.Base(file))
+
+			if err = json.Unmarshal(encoded, second); err != nil {
+				t.Fatal(err.Error())
+			}
+
+			if !reflect.DeepEqual(first, second) {
+				t.Errorf("expected %+v to equal %+v", second, first)
+			}
+
+			// The encoded document must be equal to the mock file, which uses the Opensky format
+			var expected, actual interface{}
+
+			if err = json.Unmarshal(data, &expected); err != nil {
+				t.Fatal(err.Error())
+			}
+
+			if err = json.Unmarshal(encoded, &actual); err != nil {
+				t.Fatal(err.Error())
+			}
+
+			if !reflect.DeepEqual(expected, actual) {
+				t.Errorf("expected %s to equal %s", encoded, data)
+			}
+		})
+	}
+}
+
 var getStatesInputs = []struct {
 	icao24Input string
 	timeInput   time.Time
@@ -106,6 +191,49 @@ func TestStatesService_GetAllStates(t *testing.T) {
 	}
 }
 
+func TestStatesService_GetStatesInBoundingBox(t *testing.T) {
+	mockResponseBody, err := ioutil.ReadFile("./mocks/states.json")
+
+	if err != nil {
+		t.Fatal("unexpected error in retrieving mock response body")
+	}
+
+	var query url.Values
+	mockHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write(mockResponseBody)
+	})
+	mockClient, closeServer := HTTPTestClient(mockHandler)
+	defer closeServer()
+
+	client, err := goflight.NewClient("", "", mockClient)
+
+	if err != nil {
+		t.Fatal("unexpected error in setting up Goflight client")
+	}
+
+	u, _ := url.Parse("http://example.com")
+	goflight.SetBaseURL(client, u)
+	box := goflight.BoundingBox{LaMin: 51.41, LoMin: 3.29, LaMax: 53.21, LoMax: 6.23}
+	response, err := client.States.GetStatesInBoundingBox(time.Unix(1586031310, 0), box)
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(response.States) != 6 {
+		t.Error("expect length of states in response to equal 6")
+	}
+
+	expected := url.Values{
+		"lamin": {"51.41"}, "lomin": {"3.29"}, "lamax": {"53.21"}, "lomax": {"6.23"}, "time": {"1586031310"},
+	}
+
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected query %v to equal %v", query, expected)
+	}
+}
+
 func TestStatesService_GetOwnStates(t *testing.T) {
 	mockResponseBody, err := ioutil.ReadFile("./mocks/states.json")
 
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"github.com/marcelblijleven/goflight"
+	"sort"
+	"sync"
+	"time"
+)
+
+type flightKey struct {
+	icao24    string
+	firstSeen int64
+}
+
+// MemoryStore is a Store which keeps everything in memory, e.g. for tests. It is safe for concurrent use.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	snapshots map[int64]goflight.StatesResponse
+	flights   map[flightKey]goflight.Flight
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		snapshots: make(map[int64]goflight.StatesResponse),
+		flights:   make(map[flightKey]goflight.Flight),
+	}
+}
+
+// SaveSnapshot stores the snapshot
+func (m *MemoryStore) SaveSnapshot(snapshot goflight.StatesResponse) error {
+	// Like SQLiteStore, a state replaces an earlier state of the same aircraft
+	byICAO24 := make(map[string]int, len(snapshot.States))
+	states := make([]goflight.StateVector, 0, len(snapshot.States))
+
+	for _, state := range snapshot.States {
+		if i, ok := byICAO24[state.ICAO24]; ok {
+			states[i] = state
+			continue
+		}
+
+		byICAO24[state.ICAO24] = len(states)
+		states = append(states, state)
+	}
+
+	sort.SliceStable(states, func(i, j int) bool { return states[i].ICAO24 < states[j].ICAO24 })
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshots[snapshot.Time] = goflight.StatesResponse{Time: snapshot.Time, States: states}
+
+	return nil
+}
+
+// SaveFlights stores the flights
+func (m *MemoryStore) SaveFlights(flights []goflight.Flight) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, f := range flights {
+		m.flights[flightKey{f.ICAO24, f.FirstSeen}] = f
+	}
+
+	return nil
+}
+
+// Snapshot returns the snapshot of time t
+func (m *MemoryStore) Snapshot(t time.Time) (goflight.StatesResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot, ok := m.snapshots[t.Unix()]
+
+	if !ok {
+		return goflight.StatesResponse{}, ErrSnapshotNotFound
+	}
+
+	return copySnapshot(snapshot), nil
+}
+
+// Snapshots returns the snapshots between begin and end
+func (m *MemoryStore) Snapshots(begin, end time.Time) ([]goflight.StatesResponse, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var snapshots []goflight.StatesResponse
+
+	for t, snapshot := range m.snapshots {
+		if t >= begin.Unix() && t <= end.Unix() {
+			snapshots = append(snapshots, copySnapshot(snapshot))
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Time < snapshots[j].Time })
+
+	return snapshots, nil
+}
+
+func copySnapshot(snapshot goflight.StatesResponse) goflight.StatesResponse {
+	states := make([]goflight.StateVector, len(snapshot.States))
+	copy(states, snapshot.States)
+
+	return goflight.StatesResponse{Time: snapshot.Time, States: states}
+}
+
+// StatesByICAO24 returns the states of an aircraft between begin and end
+func (m *MemoryStore) StatesByICAO24(icao24 string, begin, end time.Time) ([]goflight.StateVector, error) {
+	return m.states(begin, end, func(s *goflight.StateVector) bool { return s.ICAO24 == icao24 })
+}
+
+// StatesByCallsign returns the states with the callsign between begin and end
+func (m *MemoryStore) StatesByCallsign(callsign string, begin, end time.Time) ([]goflight.StateVector, error) {
+	callsign = trimCallsign(callsign)
+
+	return m.states(begin, end, func(s *goflight.StateVector) bool {
+		return s.Callsign != nil && trimCallsign(*s.Callsign) == callsign
+	})
+}
+
+func (m *MemoryStore) states(begin, end time.Time, match func(s *goflight.StateVector) bool) ([]goflight.StateVector, error) {
+	snapshots, err := m.Snapshots(begin, end)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var states []goflight.StateVector
+
+	for _, snapshot := range snapshots {
+		for i := range snapshot.States {
+			if match(&snapshot.States[i]) {
+				states = append(states, snapshot.States[i])
+			}
+		}
+	}
+
+	return states, nil
+}
+
+// Flights returns the flights seen between begin and end
+func (m *MemoryStore) Flights(begin, end time.Time) ([]goflight.Flight, error) {
+	return m.matchFlights(begin, end, func(f *goflight.Flight) bool { return true })
+}
+
+// FlightsByICAO24 returns the flights of an aircraft seen between begin and end
+func (m *MemoryStore) FlightsByICAO24(icao24 string, begin, end time.Time) ([]goflight.Flight, error) {
+	return m.matchFlights(begin, end, func(f *goflight.Flight) bool { return f.ICAO24 == icao24 })
+}
+
+// FlightsByCallsign returns the flights with the callsign seen between begin and end
+func (m *MemoryStore) FlightsByCallsign(callsign string, begin, end time.Time) ([]goflight.Flight, error) {
+	callsign = trimCallsign(callsign)
+
+	return m.matchFlights(begin, end, func(f *goflight.Flight) bool {
+		return f.CallSign != nil && trimCallsign(*f.CallSign) == callsign
+	})
+}
+
+func (m *MemoryStore) matchFlights(begin, end time.Time, match func(f *goflight.Flight) bool) ([]goflight.Flight, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var flights []goflight.Flight
+
+	for _, f := range m.flights {
+		if f.FirstSeen <= end.Unix() && f.LastSeen >= begin.Unix() && match(&f) {
+			flights = append(flights, f)
+		}
+	}
+
+	sort.Slice(flights, func(i, j int) bool {
+		if flights[i].FirstSeen != flights[j].FirstSeen {
+			return flights[i].FirstSeen < flights[j].FirstSeen
+		}
+
+		return flights[i].ICAO24 < flights[j].ICAO24
+	})
+
+	return flights, nil
+}
+
+// Close does nothing for a MemoryStore
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,491 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"strings"
+	"time"
+)
+
+// sqliteSchema creates the tables and indexes, the callsign indexes ignore trailing spaces
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS snapshots (
+		time INTEGER NOT NULL PRIMARY KEY
+	)`,
+	`CREATE TABLE IF NOT EXISTS states (
+		time INTEGER NOT NULL,
+		icao24 TEXT NOT NULL,
+		callsign TEXT,
+		origin_country TEXT NOT NULL,
+		time_position INTEGER,
+		last_contact INTEGER NOT NULL,
+		longitude REAL,
+		latitude REAL,
+		baro_altitude REAL,
+		on_ground INTEGER NOT NULL,
+		velocity REAL,
+		true_track REAL,
+		vertical_rate REAL,
+		sensors TEXT,
+		geo_altitude REAL,
+		squawk TEXT,
+		spi INTEGER,
+		position_source INTEGER NOT NULL,
+		category INTEGER,
+		PRIMARY KEY (time, icao24)
+	)`,
+	`CREATE INDEX IF NOT EXISTS states_icao24 ON states (icao24, time)`,
+	`CREATE INDEX IF NOT EXISTS states_callsign ON states (rtrim(callsign), time)`,
+	`CREATE TABLE IF NOT EXISTS flights (
+		icao24 TEXT NOT NULL,
+		first_seen INTEGER NOT NULL,
+		est_departure_airport TEXT,
+		last_seen INTEGER NOT NULL,
+		est_arrival_airport TEXT,
+		callsign TEXT,
+		est_departure_airport_horiz_distance INTEGER,
+		est_departure_airport_vert_distance INTEGER,
+		est_arrival_airport_horiz_distance INTEGER,
+		est_arrival_airport_vert_distance INTEGER,
+		departure_airport_candidates_count INTEGER NOT NULL,
+		arrival_airport_candidates_count INTEGER NOT NULL,
+		PRIMARY KEY (icao24, first_seen)
+	)`,
+	`CREATE INDEX IF NOT EXISTS flights_first_seen ON flights (first_seen)`,
+	`CREATE INDEX IF NOT EXISTS flights_callsign ON flights (rtrim(callsign), first_seen)`,
+}
+
+const stateColumns = `time, icao24, callsign, origin_country, time_position, last_contact, longitude, latitude,
+	baro_altitude, on_ground, velocity, true_track, vertical_rate, sensors, geo_altitude, squawk, spi,
+	position_source, category`
+
+const flightColumns = `icao24, first_seen, est_departure_airport, last_seen, est_arrival_airport, callsign,
+	est_departure_airport_horiz_distance, est_departure_airport_vert_distance, est_arrival_airport_horiz_distance,
+	est_arrival_airport_vert_distance, departure_airport_candidates_count, arrival_airport_candidates_count`
+
+// SQLiteStore is a Store backed by a SQLite database
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore creates the tables and indexes in db when they don't exist yet. The database must be opened with
+// a SQLite driver registered by the caller, e.g. sql.Open("sqlite3", "states.db") with github.com/mattn/go-sqlite3.
+// Close closes db.
+func NewSQLiteStore(db *sql.DB) (*SQLiteStore, error) {
+	for _, statement := range sqliteSchema {
+		if _, err := db.Exec(statement); err != nil {
+			return nil, err
+		}
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// OpenSQLite opens the database with a SQLite driver registered by the caller and creates a SQLiteStore. Every
+// connection to an in-memory database opens a new, empty database, so the store uses a single connection for
+// in-memory data source names like ":memory:" and "file::memory:".
+func OpenSQLite(driver, dataSourceName string) (*SQLiteStore, error) {
+	db, err := sql.Open(driver, dataSourceName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if isMemoryDSN(dataSourceName) {
+		db.SetMaxOpenConns(1)
+	}
+
+	store, err := NewSQLiteStore(db)
+
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// isMemoryDSN reports whether the data source name opens an in-memory database
+func isMemoryDSN(dataSourceName string) bool {
+	return strings.Contains(dataSourceName, ":memory:") || strings.Contains(dataSourceName, "mode=memory")
+}
+
+// SaveSnapshot stores the snapshot in a single transaction
+func (s *SQLiteStore) SaveSnapshot(snapshot goflight.StatesResponse) error {
+	return s.transaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO snapshots (time) VALUES (?)`, snapshot.Time); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM states WHERE time = ?`, snapshot.Time); err != nil {
+			return err
+		}
+
+		stmt, err := tx.Prepare(`INSERT OR REPLACE INTO states (` + stateColumns + `)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+
+		if err != nil {
+			return err
+		}
+
+		defer stmt.Close()
+
+		for _, state := range snapshot.States {
+			sensors, err := encodeSensors(state.Sensors)
+
+			if err != nil {
+				return err
+			}
+
+			_, err = stmt.Exec(snapshot.Time, state.ICAO24, stringValue(state.Callsign), state.OriginCountry,
+				int64Value(state.TimePosition), state.LastContact, float64Value(state.Longitude),
+				float64Value(state.Latitude), float64Value(state.BaroAltitude), state.OnGround,
+				float64Value(state.Velocity), float64Value(state.TrueTrack), float64Value(state.VerticalRate), sensors,
+				float64Value(state.GeoAltitude), stringValue(state.Squawk), boolValue(state.Spi),
+				int64(state.PositionSource), intValue(state.Category))
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// SaveFlights stores the flights in a single transaction
+func (s *SQLiteStore) SaveFlights(flights []goflight.Flight) error {
+	return s.transaction(func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT OR REPLACE INTO flights (` + flightColumns + `)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+
+		if err != nil {
+			return err
+		}
+
+		defer stmt.Close()
+
+		for _, f := range flights {
+			_, err = stmt.Exec(f.ICAO24, f.FirstSeen, stringValue(f.EstDepartureAirport), f.LastSeen,
+				stringValue(f.EstArrivalAirport), stringValue(f.CallSign),
+				int64Value(f.EstDepartureAirportHorizontalDistance), int64Value(f.EstDepartureAirportVerticalDistance),
+				int64Value(f.EstArrivalAirportHorizontalDistance), int64Value(f.EstArrivalAirportVerticalDistance),
+				f.DepartureAirportCandidatesCount, f.ArrivalAirportCandidatesCount)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *SQLiteStore) transaction(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Snapshot returns the snapshot of time t
+func (s *SQLiteStore) Snapshot(t time.Time) (goflight.StatesResponse, error) {
+	snapshots, err := s.Snapshots(t, t)
+
+	if err != nil {
+		return goflight.StatesResponse{}, err
+	}
+
+	if len(snapshots) == 0 {
+		return goflight.StatesResponse{}, ErrSnapshotNotFound
+	}
+
+	return snapshots[0], nil
+}
+
+// Snapshots returns the snapshots between begin and end
+func (s *SQLiteStore) Snapshots(begin, end time.Time) ([]goflight.StatesResponse, error) {
+	rows, err := s.db.Query(`SELECT time FROM snapshots WHERE time BETWEEN ? AND ? ORDER BY time`, begin.Unix(), end.Unix())
+
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []goflight.StatesResponse
+	index := make(map[int64]int)
+
+	for rows.Next() {
+		var t int64
+
+		if err = rows.Scan(&t); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		index[t] = len(snapshots)
+		snapshots = append(snapshots, goflight.StatesResponse{Time: t, States: []goflight.StateVector{}})
+	}
+
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = s.queryStates(`WHERE time BETWEEN ? AND ? ORDER BY time, icao24`, func(t int64, state goflight.StateVector) {
+		if i, ok := index[t]; ok {
+			snapshots[i].States = append(snapshots[i].States, state)
+		}
+	}, begin.Unix(), end.Unix())
+
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// StatesByICAO24 returns the states of an aircraft between begin and end
+func (s *SQLiteStore) StatesByICAO24(icao24 string, begin, end time.Time) ([]goflight.StateVector, error) {
+	return s.states(`WHERE icao24 = ? AND time BETWEEN ? AND ? ORDER BY time`, icao24, begin.Unix(), end.Unix())
+}
+
+// StatesByCallsign returns the states with the callsign between begin and end
+func (s *SQLiteStore) StatesByCallsign(callsign string, begin, end time.Time) ([]goflight.StateVector, error) {
+	return s.states(`WHERE rtrim(callsign) = ? AND time BETWEEN ? AND ? ORDER BY time, icao24`,
+		trimCallsign(callsign), begin.Unix(), end.Unix())
+}
+
+func (s *SQLiteStore) states(where string, args ...interface{}) ([]goflight.StateVector, error) {
+	var states []goflight.StateVector
+
+	err := s.queryStates(where, func(_ int64, state goflight.StateVector) {
+		states = append(states, state)
+	}, args...)
+
+	return states, err
+}
+
+// queryStates calls fn with the snapshot time and state of every row selected by the where clause
+func (s *SQLiteStore) queryStates(where string, fn func(t int64, state goflight.StateVector), args ...interface{}) error {
+	rows, err := s.db.Query(`SELECT `+stateColumns+` FROM states `+where, args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var t int64
+		var state goflight.StateVector
+		var callsign, sensors, squawk sql.NullString
+		var timePosition, category sql.NullInt64
+		var longitude, latitude, baroAltitude, velocity, trueTrack, verticalRate, geoAltitude sql.NullFloat64
+		var spi sql.NullBool
+
+		err = rows.Scan(&t, &state.ICAO24, &callsign, &state.OriginCountry, &timePosition, &state.LastContact,
+			&longitude, &latitude, &baroAltitude, &state.OnGround, &velocity, &trueTrack, &verticalRate, &sensors,
+			&geoAltitude, &squawk, &spi, &state.PositionSource, &category)
+
+		if err != nil {
+			return err
+		}
+
+		state.Callsign = nullString(callsign)
+		state.TimePosition = nullInt64(timePosition)
+		state.Longitude = nullFloat64(longitude)
+		state.Latitude = nullFloat64(latitude)
+		state.BaroAltitude = nullFloat64(baroAltitude)
+		state.Velocity = nullFloat64(velocity)
+		state.TrueTrack = nullFloat64(trueTrack)
+		state.VerticalRate = nullFloat64(verticalRate)
+		state.GeoAltitude = nullFloat64(geoAltitude)
+		state.Squawk = nullString(squawk)
+
+		if spi.Valid {
+			state.Spi = &spi.Bool
+		}
+
+		if category.Valid {
+			c := int(category.Int64)
+			state.Category = &c
+		}
+
+		if state.Sensors, err = decodeSensors(sensors); err != nil {
+			return err
+		}
+
+		fn(t, state)
+	}
+
+	return rows.Err()
+}
+
+// Flights returns the flights seen between begin and end
+func (s *SQLiteStore) Flights(begin, end time.Time) ([]goflight.Flight, error) {
+	return s.flights(`WHERE first_seen <= ? AND last_seen >= ? ORDER BY first_seen, icao24`, end.Unix(), begin.Unix())
+}
+
+// FlightsByICAO24 returns the flights of an aircraft seen between begin and end
+func (s *SQLiteStore) FlightsByICAO24(icao24 string, begin, end time.Time) ([]goflight.Flight, error) {
+	return s.flights(`WHERE icao24 = ? AND first_seen <= ? AND last_seen >= ? ORDER BY first_seen`,
+		icao24, end.Unix(), begin.Unix())
+}
+
+// FlightsByCallsign returns the flights with the callsign seen between begin and end
+func (s *SQLiteStore) FlightsByCallsign(callsign string, begin, end time.Time) ([]goflight.Flight, error) {
+	return s.flights(`WHERE rtrim(callsign) = ? AND first_seen <= ? AND last_seen >= ? ORDER BY first_seen, icao24`,
+		trimCallsign(callsign), end.Unix(), begin.Unix())
+}
+
+func (s *SQLiteStore) flights(where string, args ...interface{}) ([]goflight.Flight, error) {
+	rows, err := s.db.Query(`SELECT `+flightColumns+` FROM flights `+where, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var flights []goflight.Flight
+
+	for rows.Next() {
+		var f goflight.Flight
+		var departure, arrival, callsign sql.NullString
+		var departureHorizontal, departureVertical, arrivalHorizontal, arrivalVertical sql.NullInt64
+
+		err = rows.Scan(&f.ICAO24, &f.FirstSeen, &departure, &f.LastSeen, &arrival, &callsign, &departureHorizontal,
+			&departureVertical, &arrivalHorizontal, &arrivalVertical, &f.DepartureAirportCandidatesCount,
+			&f.ArrivalAirportCandidatesCount)
+
+		if err != nil {
+			return nil, err
+		}
+
+		f.EstDepartureAirport = nullString(departure)
+		f.EstArrivalAirport = nullString(arrival)
+		f.CallSign = nullString(callsign)
+		f.EstDepartureAirportHorizontalDistance = nullInt64(departureHorizontal)
+		f.EstDepartureAirportVerticalDistance = nullInt64(departureVertical)
+		f.EstArrivalAirportHorizontalDistance = nullInt64(arrivalHorizontal)
+		f.EstArrivalAirportVerticalDistance = nullInt64(arrivalVertical)
+		flights = append(flights, f)
+	}
+
+	return flights, rows.Err()
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// encodeSensors encodes the sensors as a JSON array, so nil and empty sensors can be told apart
+func encodeSensors(sensors *[]int) (interface{}, error) {
+	if sensors == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(*sensors)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func decodeSensors(value sql.NullString) (*[]int, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+
+	sensors := []int{}
+
+	if err := json.Unmarshal([]byte(value.String), &sensors); err != nil {
+		return nil, err
+	}
+
+	return &sensors, nil
+}
+
+// The value functions convert pointer fields to query arguments, nil pointers become NULL
+
+func stringValue(p *string) interface{} {
+	if p == nil {
+		return nil
+	}
+
+	return *p
+}
+
+func int64Value(p *int64) interface{} {
+	if p == nil {
+		return nil
+	}
+
+	return *p
+}
+
+func intValue(p *int) interface{} {
+	if p == nil {
+		return nil
+	}
+
+	return int64(*p)
+}
+
+func float64Value(p *float64) interface{} {
+	if p == nil {
+		return nil
+	}
+
+	return *p
+}
+
+func boolValue(p *bool) interface{} {
+	if p == nil {
+		return nil
+	}
+
+	return *p
+}
+
+func nullString(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.String
+}
+
+func nullInt64(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.Int64
+}
+
+func nullFloat64(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+
+	return &value.Float64
+}
+
+// trimCallsign removes the trailing spaces callsigns are padded with
+func trimCallsign(callsign string) string {
+	return strings.TrimRight(callsign, " ")
+}
@@ -0,0 +1,7 @@
+//go:build sqlite3
+// +build sqlite3
+
+package storage_test
+
+// Registers the sqlite3 driver, so TestSQLiteStore runs against a real SQLite database
+import _ "github.com/mattn/go-sqlite3"
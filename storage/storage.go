@@ -0,0 +1,41 @@
+// Package storage persists state snapshots and flights, so recent history can be queried without new requests
+// to the Opensky API.
+//
+// SQLiteStore stores the data in a SQLite database through database/sql. The package doesn't import a driver,
+// the caller registers one. MemoryStore keeps everything in memory.
+package storage
+
+import (
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"time"
+)
+
+// ErrSnapshotNotFound is returned when no snapshot was stored for the requested time
+var ErrSnapshotNotFound = errors.New("no snapshot stored for the provided time")
+
+// Store persists state snapshots and flights. All time ranges are inclusive.
+type Store interface {
+	// SaveSnapshot stores the snapshot, replacing the states of a snapshot stored earlier with the same time
+	SaveSnapshot(snapshot goflight.StatesResponse) error
+	// SaveFlights stores the flights, replacing flights stored earlier with the same icao24 and first seen time
+	SaveFlights(flights []goflight.Flight) error
+	// Snapshot returns the snapshot of time t, or ErrSnapshotNotFound
+	Snapshot(t time.Time) (goflight.StatesResponse, error)
+	// Snapshots returns the snapshots between begin and end, ordered by time
+	Snapshots(begin, end time.Time) ([]goflight.StatesResponse, error)
+	// StatesByICAO24 returns the states of an aircraft in the snapshots between begin and end, ordered by time
+	StatesByICAO24(icao24 string, begin, end time.Time) ([]goflight.StateVector, error)
+	// StatesByCallsign returns the states with the callsign in the snapshots between begin and end, ordered by
+	// time. Trailing spaces of the stored callsigns are ignored.
+	StatesByCallsign(callsign string, begin, end time.Time) ([]goflight.StateVector, error)
+	// Flights returns the flights seen between begin and end, ordered by first seen time
+	Flights(begin, end time.Time) ([]goflight.Flight, error)
+	// FlightsByICAO24 returns the flights of an aircraft seen between begin and end, ordered by first seen time
+	FlightsByICAO24(icao24 string, begin, end time.Time) ([]goflight.Flight, error)
+	// FlightsByCallsign returns the flights with the callsign seen between begin and end, ordered by first seen
+	// time. Trailing spaces of the stored callsigns are ignored.
+	FlightsByCallsign(callsign string, begin, end time.Time) ([]goflight.Flight, error)
+	// Close releases the resources of the store
+	Close() error
+}
@@ -0,0 +1,190 @@
+package storage_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"github.com/marcelblijleven/goflight/storage"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func readMock(t *testing.T, name string, v interface{}) {
+	data, err := ioutil.ReadFile("../mocks/" + name)
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err = json.Unmarshal(data, v); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+// testStore runs the same checks against every Store implementation
+func testStore(t *testing.T, store storage.Store) {
+	var snapshot goflight.StatesResponse
+	var flights []goflight.Flight
+	readMock(t, "states.json", &snapshot)
+	readMock(t, "flights.json", &flights)
+
+	sensors, category := []int{1, 2}, 4
+	snapshot.States[0].Sensors = &sensors
+	snapshot.States[0].Category = &category
+
+	later := goflight.StatesResponse{Time: snapshot.Time + 10, States: snapshot.States[:2]}
+	empty := goflight.StatesResponse{Time: snapshot.Time + 20, States: []goflight.StateVector{}}
+
+	for _, s := range []goflight.StatesResponse{later, snapshot, empty} {
+		if err := store.SaveSnapshot(s); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if err := store.SaveFlights(flights); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Saving again replaces the stored data
+	if err := store.SaveFlights(flights); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	begin, end := time.Unix(snapshot.Time, 0), time.Unix(snapshot.Time+20, 0)
+
+	t.Run("Snapshot", func(t *testing.T) {
+		stored, err := store.Snapshot(begin)
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if stored.Time != snapshot.Time || len(stored.States) != len(snapshot.States) {
+			t.Fatalf("unexpected snapshot: %+v", stored)
+		}
+
+		for _, state := range snapshot.States {
+			found := false
+
+			for _, s := range stored.States {
+				if s.ICAO24 == state.ICAO24 {
+					found = true
+
+					if !reflect.DeepEqual(s, state) {
+						t.Errorf("expected %+v to equal %+v", s, state)
+					}
+				}
+			}
+
+			if !found {
+				t.Errorf("expected state %v to be stored", state.ICAO24)
+			}
+		}
+
+		if _, err = store.Snapshot(begin.Add(time.Second)); err != storage.ErrSnapshotNotFound {
+			t.Errorf("expected error to be: %v", storage.ErrSnapshotNotFound.Error())
+		}
+	})
+
+	t.Run("Snapshots", func(t *testing.T) {
+		snapshots, err := store.Snapshots(begin.Add(time.Second), end)
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if len(snapshots) != 2 || snapshots[0].Time != later.Time || len(snapshots[0].States) != 2 ||
+			snapshots[1].Time != empty.Time || len(snapshots[1].States) != 0 {
+			t.Errorf("unexpected snapshots: %+v", snapshots)
+		}
+	})
+
+	t.Run("StatesByICAO24", func(t *testing.T) {
+		states, err := store.StatesByICAO24(snapshot.States[0].ICAO24, begin, end)
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if len(states) != 2 || !reflect.DeepEqual(states[0], snapshot.States[0]) {
+			t.Errorf("unexpected states: %+v", states)
+		}
+
+		if states, _ = store.StatesByICAO24(snapshot.States[0].ICAO24, end, end); len(states) != 0 {
+			t.Errorf("expected %v states to equal 0", len(states))
+		}
+	})
+
+	t.Run("StatesByCallsign", func(t *testing.T) {
+		// The stored callsign is "V8      "
+		states, err := store.StatesByCallsign("V8", begin, end)
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if len(states) != 2 || states[0].ICAO24 != snapshot.States[1].ICAO24 {
+			t.Errorf("unexpected states: %+v", states)
+		}
+
+		if states, _ = store.StatesByCallsign("V", begin, end); len(states) != 0 {
+			t.Errorf("expected %v states to equal 0", len(states))
+		}
+	})
+
+	t.Run("Flights", func(t *testing.T) {
+		stored, err := store.Flights(time.Unix(flights[0].FirstSeen, 0), time.Unix(flights[1].LastSeen, 0))
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if !reflect.DeepEqual(stored, flights) {
+			t.Errorf("expected %+v to equal %+v", stored, flights)
+		}
+
+		byICAO24, _ := store.FlightsByICAO24(flights[1].ICAO24, time.Unix(0, 0), time.Unix(flights[1].FirstSeen, 0))
+
+		if len(byICAO24) != 1 || byICAO24[0].ICAO24 != flights[1].ICAO24 {
+			t.Errorf("unexpected flights: %+v", byICAO24)
+		}
+
+		byCallsign, _ := store.FlightsByCallsign("TAM3533", time.Unix(flights[0].LastSeen, 0), time.Unix(flights[0].LastSeen, 0))
+
+		if len(byCallsign) != 1 || byCallsign[0].ICAO24 != flights[0].ICAO24 {
+			t.Errorf("unexpected flights: %+v", byCallsign)
+		}
+	})
+
+	if err := store.Close(); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, storage.NewMemoryStore())
+}
+
+// TestSQLiteStore runs when a SQLite driver is registered, which this package doesn't do itself. Run the tests
+// with -tags sqlite3 to register github.com/mattn/go-sqlite3.
+func TestSQLiteStore(t *testing.T) {
+	for _, driver := range sql.Drivers() {
+		if driver != "sqlite3" && driver != "sqlite" {
+			continue
+		}
+
+		store, err := storage.OpenSQLite(driver, ":memory:")
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		testStore(t, store)
+
+		return
+	}
+
+	t.Skip("no SQLite driver registered")
+}
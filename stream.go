@@ -0,0 +1,160 @@
+package goflight
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// StatesDecoder decodes the state vectors of a states response one at a time, without buffering the whole response
+type StatesDecoder struct {
+	dec      *json.Decoder
+	time     int64
+	started  bool
+	inStates bool
+	done     bool
+}
+
+// NewStatesDecoder creates a StatesDecoder which reads a states response from r
+func NewStatesDecoder(r io.Reader) *StatesDecoder {
+	return &StatesDecoder{dec: json.NewDecoder(r)}
+}
+
+// Time returns the time of the response. It is known once the time field has been read, which is before the
+// first state vector in responses of the Opensky API, and always after Next returned io.EOF.
+func (d *StatesDecoder) Time() int64 {
+	return d.time
+}
+
+// Next decodes the next state vector. It returns io.EOF when all state vectors have been decoded.
+func (d *StatesDecoder) Next() (StateVector, error) {
+	var state StateVector
+
+	if d.done {
+		return state, io.EOF
+	}
+
+	if !d.started {
+		if err := d.expectDelim('{'); err != nil {
+			return state, err
+		}
+
+		d.started = true
+	}
+
+	for {
+		if d.inStates {
+			if d.dec.More() {
+				err := d.dec.Decode(&state)
+
+				return state, err
+			}
+
+			if err := d.expectDelim(']'); err != nil {
+				return state, err
+			}
+
+			d.inStates = false
+		}
+
+		if !d.dec.More() {
+			if err := d.expectDelim('}'); err != nil {
+				return state, err
+			}
+
+			d.done = true
+
+			return state, io.EOF
+		}
+
+		token, err := d.dec.Token()
+
+		if err != nil {
+			return state, err
+		}
+
+		switch token {
+		case "time":
+			err = d.dec.Decode(&d.time)
+		case "states":
+			err = d.startStates()
+		default:
+			var skip json.RawMessage
+			err = d.dec.Decode(&skip)
+		}
+
+		if err != nil {
+			return state, err
+		}
+	}
+}
+
+// startStates reads the start of the states array, which can be null when there are no state vectors
+func (d *StatesDecoder) startStates() error {
+	token, err := d.dec.Token()
+
+	if err != nil {
+		return err
+	}
+
+	if token == nil {
+		return nil
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("unexpected %v at the start of states", token)
+	}
+
+	d.inStates = true
+
+	return nil
+}
+
+func (d *StatesDecoder) expectDelim(expected json.Delim) error {
+	token, err := d.dec.Token()
+
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != expected {
+		return fmt.Errorf("expected %v in states response, got %v", expected, token)
+	}
+
+	return nil
+}
+
+// StreamAllStates requests /api/states/all and calls fn for every state vector while the response is decoded.
+// It stops at the first error returned by fn and returns that error. The time of the response is returned
+// when all state vectors have been decoded.
+func (s *statesService) StreamAllStates(time time.Time, icao24 string, fn func(StateVector) error) (int64, error) {
+	resp, err := s.getAllStatesResponse(time, icao24, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+	decoder := NewStatesDecoder(resp.Body)
+
+	for {
+		state, err := decoder.Next()
+
+		if err == io.EOF {
+			return decoder.Time(), nil
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		if err = fn(state); err != nil {
+			return 0, err
+		}
+	}
+}
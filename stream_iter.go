@@ -0,0 +1,26 @@
+//go:build go1.23
+// +build go1.23
+
+package goflight
+
+import (
+	"io"
+	"iter"
+)
+
+// All returns an iterator over the remaining state vectors. Iteration stops after the first error.
+func (d *StatesDecoder) All() iter.Seq2[StateVector, error] {
+	return func(yield func(StateVector, error) bool) {
+		for {
+			state, err := d.Next()
+
+			if err == io.EOF {
+				return
+			}
+
+			if !yield(state, err) || err != nil {
+				return
+			}
+		}
+	}
+}
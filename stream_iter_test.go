@@ -0,0 +1,37 @@
+//go:build go1.23
+// +build go1.23
+
+package goflight_test
+
+import (
+	"github.com/marcelblijleven/goflight"
+	"os"
+	"testing"
+)
+
+func TestStatesDecoder_All(t *testing.T) {
+	f, err := os.Open("./mocks/states.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defer f.Close()
+	count := 0
+
+	for state, err := range goflight.NewStatesDecoder(f).All() {
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if state.ICAO24 == "" {
+			t.Error("expected state vector to have an icao24")
+		}
+
+		count++
+	}
+
+	if count != 6 {
+		t.Errorf("expected %v to equal 6", count)
+	}
+}
@@ -0,0 +1,216 @@
+package goflight_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeAll(t *testing.T, d *goflight.StatesDecoder) []goflight.StateVector {
+	var states []goflight.StateVector
+
+	for {
+		state, err := d.Next()
+
+		if err == io.EOF {
+			return states
+		}
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		states = append(states, state)
+	}
+}
+
+func TestStatesDecoder_Next(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/states.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var expected goflight.StatesResponse
+
+	if err = json.Unmarshal(data, &expected); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	decoder := goflight.NewStatesDecoder(bytes.NewReader(data))
+	states := decodeAll(t, decoder)
+
+	if !reflect.DeepEqual(states, expected.States) {
+		t.Errorf("expected %+v to equal %+v", states, expected.States)
+	}
+
+	if decoder.Time() != expected.Time {
+		t.Errorf("expected %v to equal %v", decoder.Time(), expected.Time)
+	}
+}
+
+var statesDecoderInputs = []struct {
+	label  string
+	input  string
+	time   int64
+	states int
+	valid  bool
+}{
+	{"time after states", `{"states": [["484ac1", null, "NL", null, 1, null, null, null, false, null, null, null, null, null, null, null, 0]], "time": 12}`, 12, 1, true},
+	{"null states", `{"time": 12, "states": null}`, 12, 0, true},
+	{"unknown fields", `{"extra": {"a": [1, 2]}, "time": 12, "states": []}`, 12, 0, true},
+	{"not an object", `[]`, 0, 0, false},
+	{"truncated", `{"time": 12, "states": [`, 12, 0, false},
+	{"invalid state", `{"time": 12, "states": [["484ac1"]]}`, 12, 0, false},
+}
+
+func TestStatesDecoder_Inputs(t *testing.T) {
+	for _, tt := range statesDecoderInputs {
+		t.Run(tt.label, func(t *testing.T) {
+			decoder := goflight.NewStatesDecoder(strings.NewReader(tt.input))
+			count := 0
+			var err error
+
+			for {
+				if _, err = decoder.Next(); err != nil {
+					break
+				}
+
+				count++
+			}
+
+			if valid := err == io.EOF; valid != tt.valid {
+				t.Fatalf("expected valid to be %v, got error %v", tt.valid, err)
+			}
+
+			if tt.valid && (count != tt.states || decoder.Time() != tt.time) {
+				t.Errorf("expected %v states at %v, got %v at %v", tt.states, tt.time, count, decoder.Time())
+			}
+		})
+	}
+}
+
+func TestStatesService_StreamAllStates(t *testing.T) {
+	mockResponseBody, err := ioutil.ReadFile("./mocks/states.json")
+
+	if err != nil {
+		t.Fatal("unexpected error in retrieving mock response body")
+	}
+
+	mockHandler := CreateTestHandler(200, mockResponseBody)
+	mockClient, closeServer := HTTPTestClient(mockHandler)
+	defer closeServer()
+
+	client, err := goflight.NewClient("", "", mockClient)
+
+	if err != nil {
+		t.Fatal("unexpected error in setting up Goflight client")
+	}
+
+	u, _ := url.Parse("http://example.com")
+	goflight.SetBaseURL(client, u)
+
+	var icao24s []string
+	responseTime, err := client.States.StreamAllStates(time.Time{}, "", func(s goflight.StateVector) error {
+		icao24s = append(icao24s, s.ICAO24)
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if responseTime != 1586031310 || len(icao24s) != 6 {
+		t.Errorf("expected 6 states at 1586031310, got %v at %v", len(icao24s), responseTime)
+	}
+
+	errStop := errors.New("stop")
+	count := 0
+	_, err = client.States.StreamAllStates(time.Time{}, "", func(s goflight.StateVector) error {
+		count++
+		return errStop
+	})
+
+	if err != errStop || count != 1 {
+		t.Errorf("expected streaming to stop after the first error, got %v after %v states", err, count)
+	}
+}
+
+// largeStatesResponse returns a states response the size of a global snapshot
+func largeStatesResponse(b *testing.B) []byte {
+	data, err := ioutil.ReadFile("./mocks/states.json")
+
+	if err != nil {
+		b.Fatal(err.Error())
+	}
+
+	var response goflight.StatesResponse
+
+	if err = json.Unmarshal(data, &response); err != nil {
+		b.Fatal(err.Error())
+	}
+
+	states := response.States
+
+	for len(response.States) < 12000 {
+		response.States = append(response.States, states...)
+	}
+
+	data, err = json.Marshal(response)
+
+	if err != nil {
+		b.Fatal(err.Error())
+	}
+
+	return data
+}
+
+func BenchmarkStatesResponse_Unmarshal(b *testing.B) {
+	data := largeStatesResponse(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		body, err := ioutil.ReadAll(bytes.NewReader(data))
+
+		if err != nil {
+			b.Fatal(err.Error())
+		}
+
+		var response goflight.StatesResponse
+
+		if err = json.Unmarshal(body, &response); err != nil {
+			b.Fatal(err.Error())
+		}
+	}
+}
+
+func BenchmarkStatesDecoder_Next(b *testing.B) {
+	data := largeStatesResponse(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		decoder := goflight.NewStatesDecoder(bytes.NewReader(data))
+
+		for {
+			_, err := decoder.Next()
+
+			if err == io.EOF {
+				break
+			}
+
+			if err != nil {
+				b.Fatal(err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package goflight
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const tracksPrefix string = "/api/tracks/"
+
+type tracksService struct {
+	client *Client
+}
+
+// Waypoint represents a point in the path of a Track
+type Waypoint struct {
+	Time         int64    // Time which the given waypoint is associated with in seconds since epoch (Unix time).
+	Latitude     *float64 // WGS-84 latitude in decimal degrees. Can be nil.
+	Longitude    *float64 // WGS-84 longitude in decimal degrees. Can be nil.
+	BaroAltitude *float64 // Barometric altitude in meters. Can be nil.
+	TrueTrack    *float64 // True track in decimal degrees clockwise from north (north=0°). Can be nil.
+	OnGround     bool     // Boolean value which indicates if the position was retrieved from a surface position report.
+}
+
+// Track represents the trajectory of an aircraft, as returned by /api/tracks/all
+type Track struct {
+	ICAO24    string     `json:"icao24"`    // Unique ICAO 24-bit address of the transponder in lower case hex string representation.
+	StartTime int64      `json:"startTime"` // Time of the first waypoint in seconds since epoch (Unix time).
+	EndTime   int64      `json:"endTime"`   // Time of the last waypoint in seconds since epoch (Unix time).
+	CallSign  *string    `json:"callsign"`  // Callsign (8 characters) that holds for the whole track. Can be nil.
+	Path      []Waypoint `json:"path"`      // Waypoints of the trajectory.
+}
+
+// UnmarshalJSON unmarshals the provided []byte onto a Waypoint struct
+func (w *Waypoint) UnmarshalJSON(buf []byte) error {
+	tmp := []interface{}{
+		&w.Time,
+		&w.Latitude,
+		&w.Longitude,
+		&w.BaroAltitude,
+		&w.TrueTrack,
+		&w.OnGround,
+	}
+
+	expectedLen := len(tmp)
+
+	if err := json.Unmarshal(buf, &tmp); err != nil {
+		return err
+	}
+
+	if actual, expected := len(tmp), expectedLen; actual != expected {
+		return errors.New("incorrect number of fields in Waypoint json")
+	}
+
+	return nil
+}
+
+// MarshalJSON marshals the Waypoint into the positional array format used by the Opensky API
+func (w Waypoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{
+		w.Time,
+		w.Latitude,
+		w.Longitude,
+		w.BaroAltitude,
+		w.TrueTrack,
+		w.OnGround,
+	})
+}
+
+// Position returns the position of the waypoint, ok is false when the position is unknown
+func (w Waypoint) Position() (p Point, ok bool) {
+	if w.Latitude == nil || w.Longitude == nil {
+		return Point{}, false
+	}
+
+	return Point{Latitude: *w.Latitude, Longitude: *w.Longitude}, true
+}
+
+// GetTrack returns the track of the aircraft at the provided time, from /api/tracks/all.
+// A zero time returns the live track of the aircraft.
+func (t *tracksService) GetTrack(icao24 string, timeParam time.Time) (Track, error) {
+	endpoint, err := url.Parse(tracksPrefix + "all")
+
+	if err != nil {
+		return Track{}, err
+	}
+
+	u := t.client.baseURL.ResolveReference(endpoint)
+	params := url.Values{}
+	params.Add("icao24", icao24)
+
+	if timeParam, ok := checkTime(timeParam); ok {
+		params.Add("time", strconv.FormatInt(timeParam.Unix(), 10))
+	} else {
+		params.Add("time", "0")
+	}
+
+	u.RawQuery = params.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+
+	if err != nil {
+		return Track{}, err
+	}
+
+	username, okUser := checkString(t.client.username)
+	password, okPassword := checkString(t.client.password)
+
+	if okUser && okPassword {
+		req.SetBasicAuth(username, password)
+	}
+
+	resp, err := t.client.httpClient.Do(req)
+
+	if err != nil {
+		return Track{}, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		if resp.StatusCode == 404 {
+			return Track{}, ErrTrackNotFound
+		}
+
+		return Track{}, fmt.Errorf("%v - %v", resp.StatusCode, resp.Status)
+	}
+
+	data, err := ioutil.ReadAll(resp.Body)
+
+	if err != nil {
+		return Track{}, err
+	}
+
+	var result Track
+
+	if err = json.Unmarshal(data, &result); err != nil {
+		return Track{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,70 @@
+package goflight_test
+
+import (
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTracksService_GetTrack(t *testing.T) {
+	mockResponseBody, err := ioutil.ReadFile("./mocks/track.json")
+
+	if err != nil {
+		t.Fatal("unexpected error in retrieving mock response body")
+	}
+
+	mockHandler := CreateTestHandler(200, mockResponseBody)
+	mockClient, closeServer := HTTPTestClient(mockHandler)
+	defer closeServer()
+
+	client, err := goflight.NewClient("", "", mockClient)
+
+	if err != nil {
+		t.Fatal("unexpected error in setting up Goflight client")
+	}
+
+	u, _ := url.Parse("http://example.com")
+	goflight.SetBaseURL(client, u)
+
+	track, err := client.Tracks.GetTrack("484ac1", time.Time{})
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if track.ICAO24 != "484ac1" {
+		t.Errorf("expected %v to equal 484ac1", track.ICAO24)
+	}
+
+	if len(track.Path) != 6 {
+		t.Fatalf("expected path to have a length of 6, got %v", len(track.Path))
+	}
+
+	if !track.Path[0].OnGround || track.Path[4].Latitude != nil {
+		t.Errorf("unexpected waypoints: %+v", track.Path)
+	}
+}
+
+func TestTracksService_GetTrack_NotFound(t *testing.T) {
+	mockHandler := CreateTestHandler(404, nil)
+	mockClient, closeServer := HTTPTestClient(mockHandler)
+	defer closeServer()
+
+	client, err := goflight.NewClient("", "", mockClient)
+
+	if err != nil {
+		t.Fatal("unexpected error in setting up Goflight client")
+	}
+
+	u, _ := url.Parse("http://example.com")
+	goflight.SetBaseURL(client, u)
+
+	_, err = client.Tracks.GetTrack("484ac1", time.Now())
+
+	if !errors.Is(err, goflight.ErrTrackNotFound) {
+		t.Errorf("expected error to be: %v", goflight.ErrTrackNotFound.Error())
+	}
+}
@@ -0,0 +1,203 @@
+package goflight
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultTrajectoryGap is the maximum time between two positions of the same track when no gap is provided
+const DefaultTrajectoryGap = 15 * time.Minute
+
+type trajectoryPoint struct {
+	waypoint Waypoint
+	callsign *string
+}
+
+// TrackBuilder reconstructs tracks from the state vectors of a sequence of snapshots. The states of every aircraft
+// are ordered by their position time and states without a new position are dropped. A track ends at a gap longer
+// than the maximum gap or when the aircraft lands. Ground movements are not part of a track, except for the last
+// position before takeoff and the first position after landing.
+type TrackBuilder struct {
+	MaxGap time.Duration // Positions further apart start a new track, DefaultTrajectoryGap when zero.
+
+	points map[string][]trajectoryPoint
+}
+
+// NewTrackBuilder creates a TrackBuilder which splits tracks at gaps longer than maxGap
+func NewTrackBuilder(maxGap time.Duration) *TrackBuilder {
+	return &TrackBuilder{MaxGap: maxGap, points: make(map[string][]trajectoryPoint)}
+}
+
+// Add adds the positions of the states in the snapshot, states without position or position time are skipped
+func (b *TrackBuilder) Add(snapshot StatesResponse) {
+	if b.points == nil {
+		b.points = make(map[string][]trajectoryPoint)
+	}
+
+	for _, s := range snapshot.States {
+		if s.TimePosition == nil || s.Latitude == nil || s.Longitude == nil {
+			continue
+		}
+
+		b.points[s.ICAO24] = append(b.points[s.ICAO24], trajectoryPoint{
+			waypoint: Waypoint{
+				Time:         *s.TimePosition,
+				Latitude:     s.Latitude,
+				Longitude:    s.Longitude,
+				BaroAltitude: s.BaroAltitude,
+				TrueTrack:    s.TrueTrack,
+				OnGround:     s.OnGround,
+			},
+			callsign: s.Callsign,
+		})
+	}
+}
+
+// Tracks returns the reconstructed tracks ordered by start time
+func (b *TrackBuilder) Tracks() []Track {
+	gap := int64(DefaultTrajectoryGap / time.Second)
+
+	if b.MaxGap > 0 {
+		gap = int64(b.MaxGap / time.Second)
+	}
+
+	var tracks []Track
+
+	for icao24, points := range b.points {
+		tracks = append(tracks, splitTrajectory(icao24, dedupeTrajectory(points), gap)...)
+	}
+
+	sort.Slice(tracks, func(i, j int) bool {
+		if tracks[i].StartTime != tracks[j].StartTime {
+			return tracks[i].StartTime < tracks[j].StartTime
+		}
+
+		return tracks[i].ICAO24 < tracks[j].ICAO24
+	})
+
+	return tracks
+}
+
+// ReconstructTracks reconstructs the tracks of all aircraft in the snapshots, see TrackBuilder
+func ReconstructTracks(snapshots []StatesResponse, maxGap time.Duration) []Track {
+	b := NewTrackBuilder(maxGap)
+
+	for _, snapshot := range snapshots {
+		b.Add(snapshot)
+	}
+
+	return b.Tracks()
+}
+
+// dedupeTrajectory orders the points by time and drops points with the time or position of the previous point
+func dedupeTrajectory(points []trajectoryPoint) []trajectoryPoint {
+	sorted := make([]trajectoryPoint, len(points))
+	copy(sorted, points)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].waypoint.Time < sorted[j].waypoint.Time })
+
+	result := sorted[:0]
+
+	for _, p := range sorted {
+		if len(result) > 0 {
+			previous := result[len(result)-1].waypoint
+
+			if p.waypoint.Time == previous.Time || samePosition(p.waypoint, previous) {
+				continue
+			}
+		}
+
+		result = append(result, p)
+	}
+
+	return result
+}
+
+func samePosition(a, b Waypoint) bool {
+	return *a.Latitude == *b.Latitude && *a.Longitude == *b.Longitude && a.OnGround == b.OnGround &&
+		equalFloat(a.BaroAltitude, b.BaroAltitude)
+}
+
+func equalFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// splitTrajectory splits the ordered points of an aircraft into tracks at gaps and landings
+func splitTrajectory(icao24 string, points []trajectoryPoint, gap int64) []Track {
+	var tracks []Track
+	var current []trajectoryPoint
+	var lastGround *trajectoryPoint
+
+	flush := func() {
+		if len(current) > 0 {
+			tracks = append(tracks, newReconstructedTrack(icao24, current))
+			current = nil
+		}
+	}
+
+	for i := range points {
+		p := &points[i]
+
+		if len(current) > 0 && p.waypoint.Time-current[len(current)-1].waypoint.Time > gap {
+			flush()
+		}
+
+		if p.waypoint.OnGround {
+			if len(current) > 0 {
+				// Landing, the first position on the ground ends the track
+				current = append(current, *p)
+				flush()
+				lastGround = nil
+			} else {
+				lastGround = p
+			}
+
+			continue
+		}
+
+		if len(current) == 0 && lastGround != nil && p.waypoint.Time-lastGround.waypoint.Time <= gap {
+			// Takeoff, the last position on the ground starts the track
+			current = append(current, *lastGround)
+		}
+
+		lastGround = nil
+		current = append(current, *p)
+	}
+
+	flush()
+
+	return tracks
+}
+
+// newReconstructedTrack creates a track with the callsign seen most often in the points
+func newReconstructedTrack(icao24 string, points []trajectoryPoint) Track {
+	track := Track{
+		ICAO24:    icao24,
+		StartTime: points[0].waypoint.Time,
+		EndTime:   points[len(points)-1].waypoint.Time,
+		Path:      make([]Waypoint, len(points)),
+	}
+
+	counts := make(map[string]int)
+	best := 0
+
+	for i, p := range points {
+		track.Path[i] = p.waypoint
+
+		if p.callsign == nil {
+			continue
+		}
+
+		counts[*p.callsign]++
+
+		if counts[*p.callsign] > best {
+			best = counts[*p.callsign]
+			track.CallSign = p.callsign
+		}
+	}
+
+	return track
+}
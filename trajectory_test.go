@@ -0,0 +1,136 @@
+package goflight_test
+
+import (
+	"github.com/marcelblijleven/goflight"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// trajectoryState creates a state at position time t, the longitude increases with the time
+func trajectoryState(icao24 string, t int64, onGround bool, callsign string) goflight.StateVector {
+	altitude := 1000.0
+
+	if onGround {
+		altitude = 0
+	}
+
+	return goflight.StateVector{
+		ICAO24:       icao24,
+		Callsign:     &callsign,
+		TimePosition: &t,
+		LastContact:  t,
+		Latitude:     floatPtr(52),
+		Longitude:    floatPtr(4 + float64(t)/1000),
+		BaroAltitude: floatPtr(altitude),
+		OnGround:     onGround,
+	}
+}
+
+// trajectorySnapshots creates a snapshot per state, at the position time of the state
+func trajectorySnapshots(states ...goflight.StateVector) []goflight.StatesResponse {
+	snapshots := make([]goflight.StatesResponse, len(states))
+
+	for i, s := range states {
+		snapshots[i] = goflight.StatesResponse{Time: *s.TimePosition, States: []goflight.StateVector{s}}
+	}
+
+	return snapshots
+}
+
+func pathTimes(track goflight.Track) []int64 {
+	times := make([]int64, len(track.Path))
+
+	for i, w := range track.Path {
+		times[i] = w.Time
+	}
+
+	return times
+}
+
+func TestReconstructTracks(t *testing.T) {
+	stale := trajectoryState("484ac1", 20, false, "KLM1    ")
+	stale.LastContact = 40
+	unchanged := trajectoryState("484ac1", 50, false, "KLM1    ")
+	unchanged.Longitude = floatPtr(4.04)
+	noPosition := trajectoryState("484ac1", 45, false, "KLM1    ")
+	noPosition.Latitude = nil
+
+	snapshots := trajectorySnapshots(
+		// Taxi and takeoff
+		trajectoryState("484ac1", 0, true, "KLM1    "),
+		trajectoryState("484ac1", 10, true, "KLM1    "),
+		trajectoryState("484ac1", 20, false, "KLM1    "),
+		// Same position report in a later snapshot
+		stale,
+		trajectoryState("484ac1", 40, false, "KLM2    "),
+		noPosition,
+		unchanged,
+		// Landing and taxi to the gate
+		trajectoryState("484ac1", 60, true, "KLM1    "),
+		trajectoryState("484ac1", 70, true, "KLM1    "),
+		// Second flight after a turnaround
+		trajectoryState("484ac1", 1000, true, "KLM3    "),
+		trajectoryState("484ac1", 1010, false, "KLM3    "),
+		// Long gap, e.g. out of coverage
+		trajectoryState("484ac1", 3000, false, "KLM3    "),
+		trajectoryState("484ac1", 3010, false, "KLM3    "),
+		// Another aircraft which only moves on the ground
+		trajectoryState("4846e1", 5, true, "V8      "),
+		trajectoryState("4846e1", 15, true, "V8      "),
+	)
+
+	// Snapshots don't have to be ordered
+	snapshots[0], snapshots[4] = snapshots[4], snapshots[0]
+	tracks := goflight.ReconstructTracks(snapshots, 15*time.Minute)
+
+	expected := []struct {
+		start, end int64
+		callsign   string
+		times      []int64
+	}{
+		{10, 60, "KLM1    ", []int64{10, 20, 40, 60}},
+		{1000, 1010, "KLM3    ", []int64{1000, 1010}},
+		{3000, 3010, "KLM3    ", []int64{3000, 3010}},
+	}
+
+	if len(tracks) != len(expected) {
+		t.Fatalf("expected %v tracks to equal %v", len(tracks), len(expected))
+	}
+
+	for i, e := range expected {
+		track := tracks[i]
+
+		if track.ICAO24 != "484ac1" || track.StartTime != e.start || track.EndTime != e.end || *track.CallSign != e.callsign {
+			t.Errorf("unexpected track %v: %+v", i, track)
+		}
+
+		if times := pathTimes(track); !reflect.DeepEqual(times, e.times) {
+			t.Errorf("expected waypoint times %v of track %v to equal %v", times, i, e.times)
+		}
+	}
+
+	first := tracks[0].Path
+
+	if !first[0].OnGround || first[1].OnGround || !first[3].OnGround {
+		t.Errorf("expected the first track to start and end on the ground: %+v", first)
+	}
+}
+
+func TestTrackBuilder_DefaultGap(t *testing.T) {
+	var b goflight.TrackBuilder
+
+	for _, s := range trajectorySnapshots(
+		trajectoryState("484ac1", 0, false, "KLM1    "),
+		trajectoryState("484ac1", 900, false, "KLM1    "),
+		trajectoryState("484ac1", 1801, false, "KLM1    "),
+	) {
+		b.Add(s)
+	}
+
+	tracks := b.Tracks()
+
+	if len(tracks) != 2 || !reflect.DeepEqual(pathTimes(tracks[0]), []int64{0, 900}) {
+		t.Errorf("unexpected tracks: %+v", tracks)
+	}
+}
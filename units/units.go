@@ -0,0 +1,70 @@
+// Package units converts the SI units used by the Opensky API to the units used in aviation
+package units
+
+import "math"
+
+const (
+	// FeetPerMeter is the number of feet in a meter
+	FeetPerMeter = 1 / 0.3048
+	// MetersPerNauticalMile is the number of meters in a nautical mile
+	MetersPerNauticalMile = 1852
+	// KnotsPerMeterPerSecond is the number of knots in a meter per second
+	KnotsPerMeterPerSecond = 3600 / 1852.0
+	// FeetPerMinutePerMeterPerSecond is the number of feet per minute in a meter per second
+	FeetPerMinutePerMeterPerSecond = FeetPerMeter * 60
+)
+
+var cardinalDirections = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+
+// MetersToFeet converts meters to feet
+func MetersToFeet(meters float64) float64 {
+	return meters * FeetPerMeter
+}
+
+// FeetToMeters converts feet to meters
+func FeetToMeters(feet float64) float64 {
+	return feet / FeetPerMeter
+}
+
+// MetersToNauticalMiles converts meters to nautical miles
+func MetersToNauticalMiles(meters float64) float64 {
+	return meters / MetersPerNauticalMile
+}
+
+// NauticalMilesToMeters converts nautical miles to meters
+func NauticalMilesToMeters(nauticalMiles float64) float64 {
+	return nauticalMiles * MetersPerNauticalMile
+}
+
+// MetersPerSecondToKnots converts meters per second to knots
+func MetersPerSecondToKnots(metersPerSecond float64) float64 {
+	return metersPerSecond * KnotsPerMeterPerSecond
+}
+
+// KnotsToMetersPerSecond converts knots to meters per second
+func KnotsToMetersPerSecond(knots float64) float64 {
+	return knots / KnotsPerMeterPerSecond
+}
+
+// MetersPerSecondToKilometersPerHour converts meters per second to kilometers per hour
+func MetersPerSecondToKilometersPerHour(metersPerSecond float64) float64 {
+	return metersPerSecond * 3.6
+}
+
+// MetersPerSecondToFeetPerMinute converts meters per second to feet per minute
+func MetersPerSecondToFeetPerMinute(metersPerSecond float64) float64 {
+	return metersPerSecond * FeetPerMinutePerMeterPerSecond
+}
+
+// FlightLevel converts a pressure altitude in meters to a flight level, the altitude in hundreds of feet
+func FlightLevel(meters float64) int {
+	return int(math.Round(MetersToFeet(meters) / 100))
+}
+
+// Cardinal converts a track in decimal degrees clockwise from north to one of the eight compass directions
+func Cardinal(degrees float64) string {
+	normalized := math.Mod(math.Mod(degrees, 360)+360, 360)
+	index := int(math.Round(normalized/45)) % len(cardinalDirections)
+
+	return cardinalDirections[index]
+}
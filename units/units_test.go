@@ -0,0 +1,59 @@
+package units_test
+
+import (
+	"github.com/marcelblijleven/goflight/units"
+	"math"
+	"testing"
+)
+
+var conversionTests = []struct {
+	label    string
+	convert  func(float64) float64
+	input    float64
+	expected float64
+}{
+	{"meters to feet", units.MetersToFeet, 3048, 10000},
+	{"feet to meters", units.FeetToMeters, 10000, 3048},
+	{"meters to nautical miles", units.MetersToNauticalMiles, 1852, 1},
+	{"nautical miles to meters", units.NauticalMilesToMeters, 2, 3704},
+	{"meters per second to knots", units.MetersPerSecondToKnots, 1852.0 / 3600 * 250, 250},
+	{"knots to meters per second", units.KnotsToMetersPerSecond, 250, 1852.0 / 3600 * 250},
+	{"meters per second to kilometers per hour", units.MetersPerSecondToKilometersPerHour, 100, 360},
+	{"meters per second to feet per minute", units.MetersPerSecondToFeetPerMinute, 5.08, 1000},
+}
+
+func TestConversions(t *testing.T) {
+	for _, tt := range conversionTests {
+		t.Run(tt.label, func(t *testing.T) {
+			if actual := tt.convert(tt.input); math.Abs(actual-tt.expected) > 1e-9 {
+				t.Errorf("expected %v to equal %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFlightLevel(t *testing.T) {
+	if fl := units.FlightLevel(9448.8); fl != 310 {
+		t.Errorf("expected %v to equal 310", fl)
+	}
+}
+
+var cardinalTests = []struct {
+	degrees  float64
+	expected string
+}{
+	{0, "N"},
+	{44, "NE"},
+	{180, "S"},
+	{328.39, "NW"},
+	{359, "N"},
+	{-90, "W"},
+}
+
+func TestCardinal(t *testing.T) {
+	for _, tt := range cardinalTests {
+		if actual := units.Cardinal(tt.degrees); actual != tt.expected {
+			t.Errorf("expected %v to equal %v for %v degrees", actual, tt.expected, tt.degrees)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package goflight
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// maxCallsignLength is the maximum length of a callsign, including the padding spaces
+const maxCallsignLength = 8
+
+// FieldError describes a problem with a single field of a StateVector or Flight, either found while decoding
+// or by Validate. Field is the snake case name of a state vector field or the json name of a flight field.
+type FieldError struct {
+	Field string      // Name of the field
+	Value interface{} // Invalid value, nil for decoding errors
+	Err   error       // Underlying error, e.g. ErrOutOfRange
+}
+
+func (e *FieldError) Error() string {
+	if e.Value == nil {
+		return fmt.Sprintf("field %q: %v", e.Field, e.Err)
+	}
+
+	return fmt.Sprintf("field %q (%v): %v", e.Field, e.Value, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned by Validate and holds an error for every invalid field
+type ValidationError []*FieldError
+
+func (e ValidationError) Error() string {
+	messages := make([]string, len(e))
+
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// validator collects the field errors of a validation
+type validator struct {
+	errors ValidationError
+}
+
+func (v *validator) add(field string, value interface{}, err error) {
+	v.errors = append(v.errors, &FieldError{Field: field, Value: value, Err: err})
+}
+
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+
+	return v.errors
+}
+
+func (v *validator) icao24(field, value string) {
+	if !isHex(value, 6) {
+		v.add(field, value, ErrInvalidICAO24)
+	}
+}
+
+func (v *validator) callsign(field string, value *string) {
+	if value != nil && len(*value) > maxCallsignLength {
+		v.add(field, *value, ErrInvalidCallsign)
+	}
+}
+
+func (v *validator) intRange(field string, value, min, max int64) {
+	if value < min || value > max {
+		v.add(field, value, ErrOutOfRange)
+	}
+}
+
+func (v *validator) floatRange(field string, value *float64, min, max float64) {
+	if value != nil && (math.IsNaN(*value) || *value < min || *value > max) {
+		v.add(field, *value, ErrOutOfRange)
+	}
+}
+
+// isHex reports whether s consists of exactly n hexadecimal digits
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isSquawk reports whether s consists of exactly 4 octal digits
+func isSquawk(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '7' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validate checks the fields of the state vector and returns a ValidationError listing every invalid field,
+// or nil when the state vector is valid
+func (s StateVector) Validate() error {
+	var v validator
+
+	v.icao24("icao24", s.ICAO24)
+	v.callsign("callsign", s.Callsign)
+
+	if s.TimePosition != nil {
+		v.intRange("time_position", *s.TimePosition, 0, math.MaxInt64)
+	}
+
+	v.intRange("last_contact", s.LastContact, 0, math.MaxInt64)
+	v.floatRange("longitude", s.Longitude, -180, 180)
+	v.floatRange("latitude", s.Latitude, -90, 90)
+	v.floatRange("velocity", s.Velocity, 0, math.MaxFloat64)
+	v.floatRange("true_track", s.TrueTrack, 0, 360)
+
+	// Altitudes and the vertical rate only need to be finite
+	v.floatRange("baro_altitude", s.BaroAltitude, -math.MaxFloat64, math.MaxFloat64)
+	v.floatRange("vertical_rate", s.VerticalRate, -math.MaxFloat64, math.MaxFloat64)
+	v.floatRange("geo_altitude", s.GeoAltitude, -math.MaxFloat64, math.MaxFloat64)
+
+	if s.Squawk != nil && !isSquawk(*s.Squawk) {
+		v.add("squawk", *s.Squawk, ErrInvalidSquawk)
+	}
+
+	// Position sources are 0 = ADS-B, 1 = ASTERIX, 2 = MLAT and 3 = FLARM
+	v.intRange("position_source", int64(s.PositionSource), 0, 3)
+
+	// Categories are 0 = no information up to 20 = line obstacle
+	if s.Category != nil {
+		v.intRange("category", int64(*s.Category), 0, 20)
+	}
+
+	return v.err()
+}
+
+// Validate checks the fields of the flight and returns a ValidationError listing every invalid field,
+// or nil when the flight is valid
+func (f Flight) Validate() error {
+	var v validator
+
+	v.icao24("icao24", f.ICAO24)
+	v.callsign("callsign", f.CallSign)
+	v.intRange("firstSeen", f.FirstSeen, 0, math.MaxInt64)
+	v.intRange("lastSeen", f.LastSeen, f.FirstSeen, math.MaxInt64)
+
+	if f.EstDepartureAirportHorizontalDistance != nil {
+		v.intRange("estDepartureAirportHorizDistance", *f.EstDepartureAirportHorizontalDistance, 0, math.MaxInt64)
+	}
+
+	if f.EstArrivalAirportHorizontalDistance != nil {
+		v.intRange("estArrivalAirportHorizDistance", *f.EstArrivalAirportHorizontalDistance, 0, math.MaxInt64)
+	}
+
+	v.intRange("departureAirportCandidatesCount", f.DepartureAirportCandidatesCount, 0, math.MaxInt64)
+	v.intRange("arrivalAirportCandidatesCount", f.ArrivalAirportCandidatesCount, 0, math.MaxInt64)
+
+	return v.err()
+}
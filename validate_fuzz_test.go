@@ -0,0 +1,69 @@
+//go:build go1.18
+// +build go1.18
+
+package goflight_test
+
+import (
+	"encoding/json"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func FuzzStateVector_RoundTrip(f *testing.F) {
+	for _, input := range decoderEquivalenceTests {
+		f.Add([]byte(input))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var s goflight.StateVector
+
+		if err := json.Unmarshal(data, &s); err != nil {
+			return
+		}
+
+		// Validate must not panic on any decodable input
+		_ = s.Validate()
+
+		encoded, err := json.Marshal(s)
+
+		if err != nil {
+			t.Fatalf("unexpected error marshalling %q: %v", data, err.Error())
+		}
+
+		var decoded goflight.StateVector
+
+		if err = json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("unexpected error unmarshalling %q: %v", encoded, err.Error())
+		}
+
+		if !reflect.DeepEqual(s, decoded) {
+			t.Fatalf("expected %+v to equal %+v", decoded, s)
+		}
+	})
+}
+
+func FuzzFlight_UnmarshalJSON(f *testing.F) {
+	data, err := ioutil.ReadFile("./mocks/flights.json")
+
+	if err != nil {
+		f.Fatal(err.Error())
+	}
+
+	f.Add(data)
+	// Decodes, but lastSeen is before firstSeen and the callsign is too long
+	f.Add([]byte(`[{"icao24":"e48809","firstSeen":1517227688,"lastSeen":1517227000,"callsign":"KLM1234567"}]`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var flights []goflight.Flight
+
+		if err := json.Unmarshal(data, &flights); err != nil {
+			return
+		}
+
+		for _, flight := range flights {
+			_ = flight.Validate()
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package goflight_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/marcelblijleven/goflight"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func readMockStateVector(t *testing.T) goflight.StateVector {
+	data, err := ioutil.ReadFile("./mocks/state_vector.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var s goflight.StateVector
+
+	if err = json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return s
+}
+
+// fieldNames returns the fields of a ValidationError, and checks that every field error wraps expected if it is non nil
+func fieldNames(t *testing.T, err error, expected error) []string {
+	var validationErr goflight.ValidationError
+
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected %v to be a ValidationError", err)
+	}
+
+	var fields []string
+
+	for _, fieldErr := range validationErr {
+		if expected != nil && !errors.Is(fieldErr, expected) {
+			t.Errorf("expected %v to wrap %v", fieldErr, expected)
+		}
+
+		fields = append(fields, fieldErr.Field)
+	}
+
+	return fields
+}
+
+var stateVectorValidateTests = []struct {
+	name     string
+	modify   func(s *goflight.StateVector)
+	expected error
+	fields   []string
+}{
+	{"uppercase icao24", func(s *goflight.StateVector) { s.ICAO24 = "A2E5EC" }, nil, nil},
+	{"short icao24", func(s *goflight.StateVector) { s.ICAO24 = "a2e5e" }, goflight.ErrInvalidICAO24, []string{"icao24"}},
+	{"non hex icao24", func(s *goflight.StateVector) { s.ICAO24 = "a2e5eg" }, goflight.ErrInvalidICAO24, []string{"icao24"}},
+	{"long callsign", func(s *goflight.StateVector) { c := "SKW36090 "; s.Callsign = &c }, goflight.ErrInvalidCallsign, []string{"callsign"}},
+	{"latitude", func(s *goflight.StateVector) { s.Latitude = floatPtr(500) }, goflight.ErrOutOfRange, []string{"latitude"}},
+	{"coordinates", func(s *goflight.StateVector) { s.Latitude, s.Longitude = floatPtr(-91), floatPtr(181) }, goflight.ErrOutOfRange, []string{"longitude", "latitude"}},
+	{"true track", func(s *goflight.StateVector) { s.TrueTrack = floatPtr(360.5) }, goflight.ErrOutOfRange, []string{"true_track"}},
+	{"negative velocity", func(s *goflight.StateVector) { s.Velocity = floatPtr(-1) }, goflight.ErrOutOfRange, []string{"velocity"}},
+	{"nan altitude", func(s *goflight.StateVector) { s.BaroAltitude = floatPtr(math.NaN()) }, goflight.ErrOutOfRange, []string{"baro_altitude"}},
+	{"infinite altitude", func(s *goflight.StateVector) { s.GeoAltitude = floatPtr(math.Inf(1)) }, goflight.ErrOutOfRange, []string{"geo_altitude"}},
+	{"negative last contact", func(s *goflight.StateVector) { s.LastContact = -1 }, goflight.ErrOutOfRange, []string{"last_contact"}},
+	{"squawk digits", func(s *goflight.StateVector) { q := "7800"; s.Squawk = &q }, goflight.ErrInvalidSquawk, []string{"squawk"}},
+	{"squawk length", func(s *goflight.StateVector) { q := "770"; s.Squawk = &q }, goflight.ErrInvalidSquawk, []string{"squawk"}},
+	{"position source", func(s *goflight.StateVector) { s.PositionSource = 9 }, goflight.ErrOutOfRange, []string{"position_source"}},
+	{"category", func(s *goflight.StateVector) { c := 21; s.Category = &c }, goflight.ErrOutOfRange, []string{"category"}},
+	{"nil fields", func(s *goflight.StateVector) { *s = goflight.StateVector{ICAO24: s.ICAO24} }, nil, nil},
+}
+
+func TestStateVector_Validate(t *testing.T) {
+	for _, tt := range stateVectorValidateTests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := readMockStateVector(t)
+			tt.modify(&s)
+			err := s.Validate()
+
+			if tt.expected == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err.Error())
+				}
+
+				return
+			}
+
+			if fields := fieldNames(t, err, tt.expected); !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("expected fields %v to equal %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestFlight_Validate(t *testing.T) {
+	data, err := ioutil.ReadFile("./mocks/flights.json")
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var flights []goflight.Flight
+
+	if err = json.Unmarshal(data, &flights); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, f := range flights {
+		if err = f.Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err.Error())
+		}
+	}
+
+	f := flights[0]
+	distance := int64(-5)
+	f.ICAO24 = "e4880"
+	f.LastSeen = f.FirstSeen - 1
+	f.EstArrivalAirportHorizontalDistance = &distance
+
+	if fields := fieldNames(t, f.Validate(), nil); !reflect.DeepEqual(fields, []string{"icao24", "lastSeen", "estArrivalAirportHorizDistance"}) {
+		t.Errorf("unexpected fields: %v", fields)
+	}
+}
+
+func TestStateVector_UnmarshalJSON_FieldError(t *testing.T) {
+	data := []byte(`["a2e5ec","SKW3609 ","United States",1586031309,1586031309,-122.5448,"47.6935",3307.08,false,143.4,155.18,-4.88,null,3147.06,"7011",false,0]`)
+	var s goflight.StateVector
+	err := json.Unmarshal(data, &s)
+	var fieldErr *goflight.FieldError
+
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("expected %v to be a FieldError", err)
+	}
+
+	if fieldErr.Field != "latitude" {
+		t.Errorf("expected field %q to equal latitude", fieldErr.Field)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+
+	if !errors.As(err, &typeErr) {
+		t.Errorf("expected %v to wrap a json.UnmarshalTypeError", err)
+	}
+}